@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"log/slog"
+
+	"github.com/comfforts/logger/field"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// badKey is the key slog uses for values that are missing a key.
+const badKey = "!BADKEY"
+
+// toZapFields converts the variadic fields accepted by AppLogger into zap
+// fields. It accepts zap.Field, []zap.Field, field.Field, []field.Field,
+// slog.Attr (including groups) and slog style alternating key/value pairs,
+// in any mix.
+func toZapFields(args []interface{}) []zap.Field {
+	if len(args) == 0 {
+		return nil
+	}
+
+	fields := make([]zap.Field, 0, len(args))
+	for len(args) > 0 {
+		switch a := args[0].(type) {
+		case zap.Field:
+			fields = append(fields, a)
+			args = args[1:]
+		case []zap.Field:
+			fields = append(fields, a...)
+			args = args[1:]
+		case field.Field:
+			fields = append(fields, a.Zap())
+			args = args[1:]
+		case []field.Field:
+			for _, f := range a {
+				fields = append(fields, f.Zap())
+			}
+			args = args[1:]
+		case slog.Attr:
+			if f, ok := attrToField(a); ok {
+				fields = append(fields, f)
+			}
+			args = args[1:]
+		case string:
+			if len(args) == 1 {
+				fields = append(fields, zap.String(badKey, a))
+				args = args[1:]
+				continue
+			}
+			if f, ok := attrToField(slog.Any(a, args[1])); ok {
+				fields = append(fields, f)
+			}
+			args = args[2:]
+		default:
+			fields = append(fields, zap.Any(badKey, a))
+			args = args[1:]
+		}
+	}
+	return fields
+}
+
+// attrToField converts a slog.Attr to a zap field, following slog's rules:
+// empty attrs are dropped and groups without a key are inlined.
+func attrToField(a slog.Attr) (zap.Field, bool) {
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return zap.Skip(), false
+		}
+		if a.Key == "" {
+			return zap.Inline(attrGroup(attrs)), true
+		}
+		return zap.Object(a.Key, attrGroup(attrs)), true
+	}
+
+	if a.Key == "" && v.Any() == nil {
+		return zap.Skip(), false
+	}
+
+	switch v.Kind() {
+	case slog.KindString:
+		return zap.String(a.Key, v.String()), true
+	case slog.KindInt64:
+		return zap.Int64(a.Key, v.Int64()), true
+	case slog.KindUint64:
+		return zap.Uint64(a.Key, v.Uint64()), true
+	case slog.KindFloat64:
+		return zap.Float64(a.Key, v.Float64()), true
+	case slog.KindBool:
+		return zap.Bool(a.Key, v.Bool()), true
+	case slog.KindDuration:
+		return zap.Duration(a.Key, v.Duration()), true
+	case slog.KindTime:
+		return zap.Time(a.Key, v.Time()), true
+	}
+
+	switch val := v.Any().(type) {
+	case field.Field:
+		zf := val.Zap()
+		zf.Key = a.Key
+		return zf, true
+	case zap.Field:
+		// A zap field passed as a slog value keeps its own encoding.
+		val.Key = a.Key
+		return val, true
+	case slog.Attr:
+		return attrToField(slog.Attr{Key: a.Key, Value: slog.GroupValue(val)})
+	}
+	return zap.Any(a.Key, v.Any()), true
+}
+
+// attrGroup encodes a slog group as a nested zap object.
+type attrGroup []slog.Attr
+
+func (g attrGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		if f, ok := attrToField(a); ok {
+			f.AddTo(enc)
+		}
+	}
+	return nil
+}
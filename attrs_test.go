@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func encodeMap(fields []zap.Field) map[string]interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
+
+func TestToZapFields(t *testing.T) {
+	err := errors.New("boom")
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "zap fields",
+			args: []interface{}{zap.String("a", "b"), []zap.Field{zap.Int("c", 1), zap.Bool("d", true)}},
+			want: map[string]interface{}{"a": "b", "c": int64(1), "d": true},
+		},
+		{
+			name: "slog attrs",
+			args: []interface{}{slog.String("s", "v"), slog.Int("i", 2), slog.Duration("d", time.Second)},
+			want: map[string]interface{}{"s": "v", "i": int64(2), "d": time.Second},
+		},
+		{
+			name: "key value pairs",
+			args: []interface{}{"k", 1, "err", err, "f", 1.5},
+			want: map[string]interface{}{"k": int64(1), "err": "boom", "f": 1.5},
+		},
+		{
+			name: "nested groups",
+			args: []interface{}{slog.Group("req", "id", 7, slog.Group("user", "name", "ann"))},
+			want: map[string]interface{}{
+				"req": map[string]interface{}{
+					"id":   int64(7),
+					"user": map[string]interface{}{"name": "ann"},
+				},
+			},
+		},
+		{
+			name: "keyless group is inlined",
+			args: []interface{}{slog.Group("", "a", 1, "b", 2)},
+			want: map[string]interface{}{"a": int64(1), "b": int64(2)},
+		},
+		{
+			name: "empty attrs and groups are dropped",
+			args: []interface{}{slog.Attr{}, slog.Group("g"), "k", "v"},
+			want: map[string]interface{}{"k": "v"},
+		},
+		{
+			name: "odd trailing key",
+			args: []interface{}{"k", "v", "dangling"},
+			want: map[string]interface{}{"k": "v", badKey: "dangling"},
+		},
+		{
+			name: "value without key",
+			args: []interface{}{42},
+			want: map[string]interface{}{badKey: int64(42)},
+		},
+		{
+			name: "mixed",
+			args: []interface{}{zap.String("z", "1"), slog.Bool("s", true), "kv", "x", []zap.Field{zap.Int("zz", 2)}},
+			want: map[string]interface{}{"z": "1", "s": true, "kv": "x", "zz": int64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encodeMap(toZapFields(tt.args))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toZapFields() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppLoggerCaller(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := newAppLogger(zap.New(core, zap.AddCaller()), nil, nil)
+
+	_, file, line, _ := runtime.Caller(0)
+	l.Info("here", "k", "v")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	caller := entries[0].Caller
+	if caller.File != file || caller.Line != line+1 {
+		t.Errorf("caller = %s:%d, want %s:%d", caller.File, caller.Line, file, line+1)
+	}
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec names the compression applied to rotated log files.
+type Codec string
+
+const (
+	CodecNone Codec = ""
+	CodecGzip Codec = "gzip"
+	CodecZstd Codec = "zstd"
+)
+
+// Compression configures post-rotation compression of log backups.
+type Compression struct {
+	Codec Codec
+	// Level is codec specific: 1-9 for gzip, 1-22 for zstd. Zero selects
+	// the codec's default.
+	Level int
+}
+
+// Ext returns the file extension the codec appends to compressed files.
+func (c Codec) Ext() string {
+	switch c {
+	case CodecGzip:
+		return ".gz"
+	case CodecZstd:
+		return ".zst"
+	}
+	return ""
+}
+
+// newWriter wraps w with the codec's compressor.
+func (c Compression) newWriter(w io.Writer) (io.WriteCloser, error) {
+	switch c.Codec {
+	case CodecGzip:
+		level := c.Level
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case CodecZstd:
+		level := zstd.SpeedDefault
+		if c.Level != 0 {
+			level = zstd.EncoderLevelFromZstd(c.Level)
+		}
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(level))
+	}
+	return nil, fmt.Errorf("unsupported compression codec %q", c.Codec)
+}
+
+// compressFile compresses src into src+ext and removes src on success.
+func (c Compression) compressFile(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst := src + c.Codec.Ext()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fi.Mode())
+	if err != nil {
+		return err
+	}
+
+	zw, err := c.newWriter(out)
+	if err == nil {
+		_, err = io.Copy(zw, in)
+		if cerr := zw.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	in.Close()
+	return os.Remove(src)
+}
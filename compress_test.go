@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap/zapcore"
+)
+
+// decompress returns the contents of a compressed backup.
+func decompress(t *testing.T, path string, codec Codec) string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var r io.Reader
+	switch codec {
+	case CodecGzip:
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	case CodecZstd:
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decompressing %s: %v", path, err)
+	}
+	return string(b)
+}
+
+// generations returns the distinct generation numbers of the lines written
+// by writeGenerations.
+func generations(t *testing.T, content string) []int {
+	t.Helper()
+
+	seen := map[int]bool{}
+	sc := bufio.NewScanner(strings.NewReader(content))
+	sc.Buffer(nil, 1024*1024)
+	for sc.Scan() {
+		gen, err := strconv.Atoi(strings.SplitN(sc.Text(), " ", 2)[0])
+		if err != nil {
+			t.Fatalf("unexpected line %.40q", sc.Text())
+		}
+		seen[gen] = true
+	}
+
+	var gens []int
+	for g := range seen {
+		gens = append(gens, g)
+	}
+	sort.Ints(gens)
+	return gens
+}
+
+// writeGenerations writes n generations of 1 MiB each, so a 1 MB MaxSize
+// rotates at the start of every generation after the first.
+func writeGenerations(t *testing.T, w io.Writer, n int) {
+	t.Helper()
+
+	const chunk = 256 * 1024
+	for gen := 0; gen < n; gen++ {
+		// Keep backup stamps, which have millisecond resolution, distinct.
+		time.Sleep(2 * time.Millisecond)
+
+		prefix := strconv.Itoa(gen) + " "
+		line := []byte(prefix + strings.Repeat("x", chunk-len(prefix)-1) + "\n")
+		for i := 0; i < 4; i++ {
+			if _, err := w.Write(line); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestCompressedRotation(t *testing.T) {
+	backupName := regexp.MustCompile(`^app-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}(\.\d+)?\.log(\.gz|\.zst)$`)
+
+	tests := []struct {
+		engine RotationEngine
+		codec  Codec
+		level  int
+	}{
+		{RotationLumberjack, CodecGzip, 0},
+		{RotationLumberjack, CodecZstd, 0},
+		{RotationNative, CodecGzip, 0},
+		{RotationNative, CodecZstd, 0},
+		{RotationNative, CodecGzip, 9},
+		{RotationLumberjack, CodecZstd, 19},
+	}
+
+	for _, tt := range tests {
+		name := string(tt.engine) + "/" + string(tt.codec) + "/" + strconv.Itoa(tt.level)
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+
+			w := newRotator(path,
+				Rotation{Engine: tt.engine, MaxSize: 1, MaxBackups: 2},
+				Compression{Codec: tt.codec, Level: tt.level},
+				zapcore.DefaultClock,
+			)
+			writeGenerations(t, w, 4)
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var backups []string
+			for _, e := range entries {
+				if e.Name() == "app.log" {
+					continue
+				}
+				if !backupName.MatchString(e.Name()) || !strings.HasSuffix(e.Name(), tt.codec.Ext()) {
+					t.Errorf("unexpected file %s", e.Name())
+					continue
+				}
+				backups = append(backups, e.Name())
+			}
+			sort.Strings(backups)
+			if len(backups) != 2 {
+				t.Fatalf("got backups %v, want MaxBackups 2", backups)
+			}
+
+			for i, b := range backups {
+				gens := generations(t, decompress(t, filepath.Join(dir, b), tt.codec))
+				if len(gens) != 1 || gens[0] != i+1 {
+					t.Errorf("%s holds generations %v, want [%d]", b, gens, i+1)
+				}
+			}
+
+			current, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gens := generations(t, string(current)); len(gens) != 1 || gens[0] != 3 {
+				t.Errorf("app.log holds generations %v, want [3]", gens)
+			}
+		})
+	}
+}
+
+func TestCompressFileInvalidLevel(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "app-backup.log")
+	if err := os.WriteFile(src, []byte("record\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Compression{Codec: CodecGzip, Level: 42}.compressFile(src)
+	if err == nil {
+		t.Fatal("compressFile with gzip level 42 succeeded")
+	}
+	if b, err := os.ReadFile(src); err != nil || !bytes.Equal(b, []byte("record\n")) {
+		t.Errorf("source after failed compression = %q, %v", b, err)
+	}
+	if _, err := os.Stat(src + ".gz"); !os.IsNotExist(err) {
+		t.Errorf("partial %s.gz left behind: %v", src, err)
+	}
+}
+
+// lumberjack rotates an existing file on open when the first write would
+// fill it exactly; the backup must still be compressed.
+func TestLumberjackRotationOnOpenCompressed(t *testing.T) {
+	if DEFAULT_ROTATION_ENGINE != RotationLumberjack {
+		t.Skip("built without lumberjack")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	const max = 1024 * 1024
+	if err := os.WriteFile(path, bytes.Repeat([]byte("x"), max-10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newRotator(path, Rotation{Engine: RotationLumberjack, MaxSize: 1}, Compression{Codec: CodecGzip}, zapcore.DefaultClock)
+	if _, err := w.Write([]byte("123456789\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+	if len(matches) != 1 {
+		entries, _ := os.ReadDir(dir)
+		t.Fatalf("got compressed backups %v, want 1 (dir: %v)", matches, entries)
+	}
+	if got := decompress(t, matches[0], CodecGzip); len(got) != max-10 {
+		t.Errorf("backup holds %d bytes, want %d", len(got), max-10)
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DebugContext logs at DebugLevel, annotating the record from ctx when the
+// logger was built WithContextAnnotations.
+func (l *appLogger) DebugContext(ctx context.Context, msg string, fields ...interface{}) {
+	l.fields.Debug(msg, l.contextFields(ctx, fields)...)
+}
+
+// InfoContext logs at InfoLevel, see DebugContext.
+func (l *appLogger) InfoContext(ctx context.Context, msg string, fields ...interface{}) {
+	l.fields.Info(msg, l.contextFields(ctx, fields)...)
+}
+
+// WarnContext logs at WarnLevel, see DebugContext.
+func (l *appLogger) WarnContext(ctx context.Context, msg string, fields ...interface{}) {
+	l.fields.Warn(msg, l.contextFields(ctx, fields)...)
+}
+
+// ErrorContext logs at ErrorLevel, see DebugContext.
+func (l *appLogger) ErrorContext(ctx context.Context, msg string, fields ...interface{}) {
+	l.fields.Error(msg, l.contextFields(ctx, fields)...)
+}
+
+func (l *appLogger) contextFields(ctx context.Context, fields []interface{}) []zap.Field {
+	zf := toZapFields(fields)
+	if ctx == nil || l.config == nil || !l.config.ContextAnnotations {
+		return zf
+	}
+	now := time.Now()
+	if l.config.Clock != nil {
+		now = l.config.Clock.Now()
+	}
+	return append(zf, contextAnnotations(ctx, now)...)
+}
+
+// contextAnnotations describes ctx's deadline, relative to now, and its
+// cancellation state.
+func contextAnnotations(ctx context.Context, now time.Time) []zap.Field {
+	fields := make([]zap.Field, 0, 3)
+	if deadline, ok := ctx.Deadline(); ok {
+		fields = append(fields, zap.Duration("ctx_deadline_remaining", deadline.Sub(now)))
+	}
+
+	err := ctx.Err()
+	fields = append(fields, zap.Bool("ctx_canceled", err != nil))
+	if err != nil {
+		fields = append(fields, zap.String("ctx_err", err.Error()))
+	}
+	return fields
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *appLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, or a logger that discards
+// everything if there is none.
+func FromContext(ctx context.Context) *appLogger {
+	if l, ok := ctx.Value(contextKey{}).(*appLogger); ok {
+		return l
+	}
+	return newAppLogger(zap.NewNop(), nil, nil)
+}
+
+// AppendToContext stores a child of ctx's logger with fields added, so
+// each layer of a request can enrich the logger without knowing its type:
+//
+//	ctx = logger.AppendToContext(ctx, "route", route)
+//	ctx = logger.AppendToContext(ctx, "user", userID)
+//
+// ctx must already carry a logger from NewContext. Otherwise the child
+// discards everything, like FromContext's fallback, and the mistake is
+// reported through Internal.
+func AppendToContext(ctx context.Context, fields ...interface{}) context.Context {
+	l, ok := ctx.Value(contextKey{}).(*appLogger)
+	if !ok {
+		Internal().Warn("AppendToContext called without a logger in the context, fields are discarded",
+			zap.Int("fields", len(fields)))
+		l = FromContext(ctx)
+	}
+	return NewContext(ctx, l.WithFields(fields...))
+}
+
+// WithFields returns a child logger that adds fields to every record,
+// accepting the same field forms as the level methods.
+func (l *appLogger) WithFields(fields ...interface{}) *appLogger {
+	return newAppLogger(l.Logger.With(toZapFields(fields)...), l.config, l.sinks)
+}
@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+	"go.uber.org/zap"
+)
+
+func TestContextAnnotationsUseLoggerClock(t *testing.T) {
+	l, sink, clock := logtest.NewLogger(t, logger.WithContextAnnotations())
+
+	ctx, cancel := context.WithDeadline(context.Background(), clock.Now().Add(5*time.Second))
+	defer cancel()
+
+	l.InfoContext(ctx, "first")
+	clock.Add(1500 * time.Millisecond)
+	l.InfoContext(ctx, "second")
+
+	lines := sink.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("got %d records, want 2", len(lines))
+	}
+	// Durations are encoded as float seconds.
+	for i, want := range []string{`"ctx_deadline_remaining":5,`, `"ctx_deadline_remaining":3.5,`} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("record %d = %s, want %s", i, lines[i], want)
+		}
+	}
+}
+
+func TestAppendToContext(t *testing.T) {
+	l, sink, _ := logtest.NewLogger(t)
+
+	root := logger.NewContext(context.Background(), l)
+	route := logger.AppendToContext(root, "route", "/a")
+	user := logger.AppendToContext(route, "user", "u1")
+	attempt := logger.AppendToContext(user, zap.Int("attempt", 2))
+
+	logger.FromContext(attempt).Info("attempt")
+	logger.FromContext(route).Info("route")
+	logger.FromContext(root).Info("root")
+	l.Info("parent")
+
+	want := []string{
+		`"msg":"attempt","route":"/a","user":"u1","attempt":2}`,
+		`"msg":"route","route":"/a"}`,
+		`"msg":"root"}`,
+		`"msg":"parent"}`,
+	}
+	lines := sink.Lines()
+	if len(lines) != len(want) {
+		t.Fatalf("got %d records, want %d:\n%v", len(lines), len(want), lines)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Errorf("record %d = %s, want suffix %s", i, lines[i], w)
+		}
+	}
+}
+
+func TestAppendToContextWithoutLogger(t *testing.T) {
+	internal := &slowWriter{}
+	setInternalOutput(t, internal)
+
+	ctx := logger.AppendToContext(context.Background(), "route", "/a")
+	logger.FromContext(ctx).Info("discarded")
+
+	_ = logger.Internal().Sync()
+	if internal.count("AppendToContext called without a logger") != 1 {
+		t.Errorf("missing logger not reported:\n%s", internal.buf.String())
+	}
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fileCheckInterval is how often a healthy file output checks that its
+// file still exists.
+const fileCheckInterval = 5 * time.Second
+
+// ValidateLogPath checks that the log file at path can be created or
+// appended to, creating its directory if needed.
+func ValidateLogPath(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	return f.Close()
+}
+
+// rotator is implemented by both rotation engines.
+type rotator interface {
+	io.WriteCloser
+	Rotate() error
+}
+
+// fallbackWriter writes to a log file and falls back to stderr while the
+// file can't be written, e.g. after a permission change or with a full
+// disk. A deleted file is recreated. State changes are reported through
+// Internal.
+type fallbackWriter struct {
+	path     string
+	primary  rotator
+	fallback zapcore.WriteSyncer
+	now      func() time.Time
+
+	mu        sync.Mutex
+	err       error
+	lastCheck time.Time
+}
+
+func newFallbackWriter(path string, primary rotator, clock zapcore.Clock) *fallbackWriter {
+	w := &fallbackWriter{
+		path:      path,
+		primary:   primary,
+		fallback:  zapcore.Lock(os.Stderr),
+		now:       clock.Now,
+		lastCheck: clock.Now(),
+	}
+
+	if err := ValidateLogPath(path); err != nil {
+		w.err = err
+		Internal().Error("log file not writable, logging to stderr",
+			zap.String("path", path), zap.Error(err))
+	}
+	return w
+}
+
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err == nil {
+		w.checkExists()
+	}
+
+	n, err := w.primary.Write(p)
+	if err == nil {
+		if w.err != nil {
+			Internal().Info("log file writable again, leaving stderr fallback",
+				zap.String("path", w.path), zap.NamedError("previous_error", w.err))
+			w.err = nil
+		}
+		return n, nil
+	}
+
+	if w.err == nil {
+		Internal().Error("log file write failed, logging to stderr",
+			zap.String("path", w.path), zap.Error(err))
+	}
+	w.err = err
+	return w.fallback.Write(p)
+}
+
+// checkExists recreates the log file if it can no longer be found, e.g. it
+// or its directory was removed, since writes to an unlinked file succeed
+// but are lost. If it can't be recreated the next write falls back.
+func (w *fallbackWriter) checkExists() {
+	now := w.now()
+	if now.Sub(w.lastCheck) < fileCheckInterval {
+		return
+	}
+	w.lastCheck = now
+
+	if _, err := os.Stat(w.path); err == nil {
+		return
+	}
+
+	Internal().Warn("log file removed, reopening", zap.String("path", w.path))
+	if err := w.primary.Rotate(); err != nil {
+		Internal().Error("reopening log file", zap.String("path", w.path), zap.Error(err))
+	}
+}
+
+func (w *fallbackWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if s, ok := w.primary.(zapcore.WriteSyncer); ok && w.err == nil {
+		return s.Sync()
+	}
+	return nil
+}
+
+func (w *fallbackWriter) Close() error {
+	return w.primary.Close()
+}
+
+// degraded returns the error that caused the fallback, nil while the file
+// is being written.
+func (w *fallbackWriter) degraded() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
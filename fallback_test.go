@@ -0,0 +1,150 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+)
+
+// fileStats returns the stats of a logger's only output.
+func fileStats(t *testing.T, l *logger.Logger) logger.SinkStats {
+	t.Helper()
+
+	st := l.Stats()
+	if len(st) != 1 {
+		t.Fatalf("got %d sink stats, want 1", len(st))
+	}
+	return st[0]
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestFallbackInvalidPathAtStartup(t *testing.T) {
+	internal := &slowWriter{}
+	setInternalOutput(t, internal)
+
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(blocker, "app.log")
+
+	if err := logger.ValidateLogPath(path); err == nil {
+		t.Fatal("ValidateLogPath succeeded below a regular file")
+	}
+
+	l := nativeFileLogger(path, logtest.NewClock(logtest.DefaultStart), logger.Rotation{})
+	l.Info("to stderr")
+
+	st := fileStats(t, l)
+	if st.Records != 1 || st.Errors != 1 || st.Drops != 0 || !st.Failing {
+		t.Errorf("stats = %+v, want one error and no drops", st)
+	}
+	if l.Healthy() {
+		t.Error("logger healthy while writing to the fallback")
+	}
+
+	_ = logger.Internal().Sync()
+	if internal.count("log file not writable, logging to stderr") != 1 {
+		t.Errorf("startup failure not reported:\n%s", internal.buf.String())
+	}
+}
+
+func TestFallbackAndRecovery(t *testing.T) {
+	internal := &slowWriter{}
+	setInternalOutput(t, internal)
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "app.log")
+	clock := logtest.NewClock(logtest.DefaultStart)
+	l := nativeFileLogger(path, clock, logger.Rotation{})
+	defer l.Close()
+
+	l.Info("first")
+
+	// Replace the log directory with a regular file, so the file can be
+	// neither found nor recreated.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	clock.Add(5 * time.Second)
+	l.Info("to stderr")
+
+	st := fileStats(t, l)
+	if st.Errors != 1 || st.Drops != 0 || !st.Failing || l.Healthy() {
+		t.Errorf("stats after failure = %+v, want one error and no drops", st)
+	}
+
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("recovered")
+
+	st = fileStats(t, l)
+	if st.Records != 3 || st.Errors != 1 || st.Failing || !l.Healthy() {
+		t.Errorf("stats after recovery = %+v", st)
+	}
+	if got := readLog(t, path); strings.Count(got, "\n") != 1 || !strings.Contains(got, "recovered") {
+		t.Errorf("log file = %q, want only the recovered record", got)
+	}
+
+	_ = logger.Internal().Sync()
+	for _, msg := range []string{"log file write failed, logging to stderr", "log file writable again, leaving stderr fallback"} {
+		if internal.count(msg) != 1 {
+			t.Errorf("%q not reported once:\n%s", msg, internal.buf.String())
+		}
+	}
+}
+
+func TestFallbackRecreatesDeletedFile(t *testing.T) {
+	internal := &slowWriter{}
+	setInternalOutput(t, internal)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	clock := logtest.NewClock(logtest.DefaultStart)
+	l := nativeFileLogger(path, clock, logger.Rotation{})
+	defer l.Close()
+
+	l.Info("first")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Within the check interval the write goes to the unlinked file.
+	clock.Add(4 * time.Second)
+	l.Info("lost")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("log file recreated before the check interval: %v", err)
+	}
+
+	clock.Add(time.Second)
+	l.Info("second")
+	if got := readLog(t, path); strings.Count(got, "\n") != 1 || !strings.Contains(got, "second") {
+		t.Errorf("log file = %q, want only the second record", got)
+	}
+	if !l.Healthy() {
+		t.Error("logger unhealthy after recreating its file")
+	}
+
+	_ = logger.Internal().Sync()
+	if internal.count("log file removed, reopening") != 1 {
+		t.Errorf("removal not reported:\n%s", internal.buf.String())
+	}
+}
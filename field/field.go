@@ -0,0 +1,172 @@
+// Package field provides typed, generic constructors for structured log
+// fields. Fields are passed without boxing to the logger package's
+// LogFields method, and convert to zap fields and slog attributes without
+// reflection for use with zap loggers and the slog handler.
+package field
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+	"unsafe"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Signed is satisfied by the signed integer types.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is satisfied by the unsigned integer types.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Floating is satisfied by the floating point types.
+type Floating interface {
+	~float32 | ~float64
+}
+
+// Field is a typed key/value pair.
+type Field struct {
+	zf zapcore.Field
+}
+
+// Key returns the field's key.
+func (f Field) Key() string {
+	return f.zf.Key
+}
+
+// Zap returns the field as a zap field.
+func (f Field) Zap() zapcore.Field {
+	return f.zf
+}
+
+// Attr returns the field as a slog attribute.
+func (f Field) Attr() slog.Attr {
+	zf := f.zf
+	switch zf.Type {
+	case zapcore.StringType:
+		return slog.String(zf.Key, zf.String)
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return slog.Int64(zf.Key, zf.Integer)
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		return slog.Uint64(zf.Key, uint64(zf.Integer))
+	case zapcore.Float64Type:
+		return slog.Float64(zf.Key, math.Float64frombits(uint64(zf.Integer)))
+	case zapcore.Float32Type:
+		return slog.Float64(zf.Key, float64(math.Float32frombits(uint32(zf.Integer))))
+	case zapcore.BoolType:
+		return slog.Bool(zf.Key, zf.Integer == 1)
+	case zapcore.DurationType:
+		return slog.Duration(zf.Key, time.Duration(zf.Integer))
+	case zapcore.TimeType:
+		t := time.Unix(0, zf.Integer)
+		if loc, ok := zf.Interface.(*time.Location); ok {
+			t = t.In(loc)
+		}
+		return slog.Time(zf.Key, t)
+	case zapcore.TimeFullType:
+		return slog.Time(zf.Key, zf.Interface.(time.Time))
+	case zapcore.StringerType:
+		return slog.String(zf.Key, zf.Interface.(fmt.Stringer).String())
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		zf.AddTo(enc)
+		if zf.Type == zapcore.InlineMarshalerType {
+			return slog.Any(zf.Key, enc.Fields)
+		}
+		return slog.Any(zf.Key, enc.Fields[zf.Key])
+	}
+	return slog.Any(zf.Key, zf.Interface)
+}
+
+// String constructs a field holding a string.
+func String[T ~string](key string, v T) Field {
+	return Field{zap.String(key, string(v))}
+}
+
+// Int constructs a field holding a signed integer.
+func Int[T Signed](key string, v T) Field {
+	return Field{zap.Int64(key, int64(v))}
+}
+
+// Uint constructs a field holding an unsigned integer.
+func Uint[T Unsigned](key string, v T) Field {
+	return Field{zap.Uint64(key, uint64(v))}
+}
+
+// Float constructs a field holding a floating point number.
+func Float[T Floating](key string, v T) Field {
+	return Field{zap.Float64(key, float64(v))}
+}
+
+// Bool constructs a field holding a bool.
+func Bool(key string, v bool) Field {
+	return Field{zap.Bool(key, v)}
+}
+
+// Duration constructs a field holding a time.Duration.
+func Duration(key string, v time.Duration) Field {
+	return Field{zap.Duration(key, v)}
+}
+
+// Time constructs a field holding a time.Time.
+func Time(key string, v time.Time) Field {
+	return Field{zap.Time(key, v)}
+}
+
+// Err constructs a field holding err under the key "error".
+func Err(err error) Field {
+	return Field{zap.Error(err)}
+}
+
+// NamedErr constructs a field holding err under key.
+func NamedErr(key string, err error) Field {
+	return Field{zap.NamedError(key, err)}
+}
+
+// Stringer constructs a field holding the value of v.String(), evaluated
+// when the record is encoded.
+func Stringer[T fmt.Stringer](key string, v T) Field {
+	return Field{zap.Stringer(key, v)}
+}
+
+// Object constructs a field holding a nested object.
+func Object[T zapcore.ObjectMarshaler](key string, v T) Field {
+	return Field{zap.Object(key, v)}
+}
+
+// Array constructs a field holding a list of values.
+func Array[T zapcore.ArrayMarshaler](key string, v T) Field {
+	return Field{zap.Array(key, v)}
+}
+
+// Any constructs a field for a value of any type, choosing the most
+// specific encoding available.
+func Any[T any](key string, v T) Field {
+	return Field{zap.Any(key, v)}
+}
+
+// ZapFields returns fs as zap fields without copying. Field is a single
+// zapcore.Field, so the two slices share the same memory layout. The
+// result aliases fs: callers must not modify either slice while the other
+// is in use. Copy the result before changing it.
+func ZapFields(fs []Field) []zapcore.Field {
+	if len(fs) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*zapcore.Field)(unsafe.Pointer(unsafe.SliceData(fs))), len(fs))
+}
+
+// Attrs returns fs as slog attributes, for use with slog.Logger.LogAttrs.
+func Attrs(fs ...Field) []slog.Attr {
+	attrs := make([]slog.Attr, len(fs))
+	for i, f := range fs {
+		attrs[i] = f.Attr()
+	}
+	return attrs
+}
@@ -0,0 +1,141 @@
+package field
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type point struct{ x, y int }
+
+func (p point) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("x", p.x)
+	enc.AddInt("y", p.y)
+	return nil
+}
+
+type ints []int
+
+func (is ints) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, i := range is {
+		enc.AppendInt(i)
+	}
+	return nil
+}
+
+type level string
+
+func (l level) String() string { return "level:" + string(l) }
+
+// attrEqual compares attributes, including unhashable values of kind Any.
+func attrEqual(a, b slog.Attr) bool {
+	if a.Key != b.Key || a.Value.Kind() != b.Value.Kind() {
+		return false
+	}
+	if a.Value.Kind() == slog.KindAny {
+		return reflect.DeepEqual(a.Value.Any(), b.Value.Any())
+	}
+	return a.Equal(b)
+}
+
+func TestAttr(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	ts := time.Date(2024, 3, 1, 10, 0, 0, 0, loc)
+	// Times outside int64 nanoseconds are kept whole by zap.
+	far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := errors.New("boom")
+
+	tests := []struct {
+		name  string
+		field Field
+		want  slog.Attr
+	}{
+		{"string", String("k", "v"), slog.String("k", "v")},
+		{"named string", String("k", level("debug")), slog.String("k", "debug")},
+		{"int8", Int("k", int8(-8)), slog.Int64("k", -8)},
+		{"int", Int("k", 42), slog.Int64("k", 42)},
+		{"uint16", Uint("k", uint16(16)), slog.Uint64("k", 16)},
+		{"uint64 max", Uint("k", uint64(math.MaxUint64)), slog.Uint64("k", math.MaxUint64)},
+		{"float32", Float("k", float32(1.5)), slog.Float64("k", 1.5)},
+		{"float64", Float("k", 2.25), slog.Float64("k", 2.25)},
+		{"bool", Bool("k", true), slog.Bool("k", true)},
+		{"duration", Duration("k", time.Second), slog.Duration("k", time.Second)},
+		{"time", Time("k", ts), slog.Time("k", ts)},
+		{"time full", Time("k", far), slog.Time("k", far)},
+		{"error", Err(err), slog.Any("error", err)},
+		{"named error", NamedErr("cause", err), slog.Any("cause", err)},
+		{"stringer", Stringer("k", level("warn")), slog.String("k", "level:warn")},
+		{"object", Object("k", point{1, 2}), slog.Any("k", map[string]interface{}{"x": 1, "y": 2})},
+		{"inline", Field{zap.Inline(point{3, 4})}, slog.Any("", map[string]interface{}{"x": 3, "y": 4})},
+		{"array", Array("k", ints{1, 2}), slog.Any("k", ints{1, 2})},
+		{"any int32", Any("k", int32(7)), slog.Int64("k", 7)},
+		{"any uint8", Any("k", uint8(8)), slog.Uint64("k", 8)},
+		{"any uintptr", Any("k", uintptr(9)), slog.Uint64("k", 9)},
+		{"any float32", Any("k", float32(0.5)), slog.Float64("k", 0.5)},
+		{"any bytes", Any("k", []byte("raw")), slog.Any("k", []byte("raw"))},
+		{"any map", Any("k", map[string]int{"a": 1}), slog.Any("k", map[string]int{"a": 1})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Attr(); !attrEqual(got, tt.want) {
+				t.Errorf("Attr() = %v (%v), want %v (%v)", got, got.Value.Kind(), tt.want, tt.want.Value.Kind())
+			}
+			if got := tt.field.Key(); got != tt.want.Key {
+				t.Errorf("Key() = %q, want %q", got, tt.want.Key)
+			}
+		})
+	}
+}
+
+func TestTimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	ts := time.Date(2024, 3, 1, 10, 0, 0, 0, loc)
+
+	got := Time("k", ts).Attr().Value.Time()
+	if got.Location() != loc || !got.Equal(ts) {
+		t.Errorf("time = %v, want %v", got, ts)
+	}
+}
+
+func TestZapFields(t *testing.T) {
+	if ZapFields(nil) != nil {
+		t.Error("ZapFields(nil) != nil")
+	}
+
+	fs := []Field{String("a", "b"), Int("c", 1)}
+	zfs := ZapFields(fs)
+	if len(zfs) != 2 || !zfs[0].Equals(zap.String("a", "b")) || !zfs[1].Equals(zap.Int64("c", 1)) {
+		t.Fatalf("ZapFields() = %#v", zfs)
+	}
+	for i := range fs {
+		if !fs[i].Zap().Equals(zfs[i]) {
+			t.Errorf("Zap() = %#v, want %#v", fs[i].Zap(), zfs[i])
+		}
+	}
+
+	// The result aliases its argument.
+	fs[0] = Bool("d", true)
+	if !zfs[0].Equals(zap.Bool("d", true)) {
+		t.Errorf("ZapFields() result does not alias its argument: %#v", zfs[0])
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	attrs := Attrs(String("a", "b"), Int("c", 1))
+	want := []slog.Attr{slog.String("a", "b"), slog.Int64("c", 1)}
+	if len(attrs) != len(want) {
+		t.Fatalf("Attrs() = %v, want %v", attrs, want)
+	}
+	for i := range want {
+		if !attrs[i].Equal(want[i]) {
+			t.Errorf("Attrs()[%d] = %v, want %v", i, attrs[i], want[i])
+		}
+	}
+}
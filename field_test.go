@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/comfforts/logger/field"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func discardLogger() *appLogger {
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	core := zapcore.NewCore(enc, zapcore.AddSync(io.Discard), zapcore.DebugLevel)
+	return newAppLogger(zap.New(core), nil, nil)
+}
+
+func TestLogFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := newAppLogger(zap.New(core, zap.AddCaller()), nil, nil)
+
+	_, file, line, _ := runtime.Caller(0)
+	l.LogFields(zapcore.WarnLevel, "typed", field.String("s", "v"), field.Int("i", int8(3)), field.Err(errors.New("boom")))
+	l.LogFields(zapcore.DebugLevel, "no fields")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Caller.File != file || entries[0].Caller.Line != line+1 {
+		t.Errorf("caller = %s:%d, want %s:%d", entries[0].Caller.File, entries[0].Caller.Line, file, line+1)
+	}
+
+	got := entries[0].ContextMap()
+	if got["s"] != "v" || got["i"] != int64(3) || got["error"] != "boom" {
+		t.Errorf("fields = %v", got)
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg", LevelKey: "level", EncodeLevel: zapcore.LowercaseLevelEncoder})
+	l := slog.New(NewSlogHandler(zap.New(zapcore.NewCore(enc, zapcore.AddSync(&buf), zapcore.InfoLevel))))
+
+	l.Debug("dropped")
+	l.With("svc", "api").WithGroup("req").Warn("slow", "id", 7, field.Int("n", 2).Attr(), slog.Any("typed", field.Bool("ignored", true)))
+
+	want := `{"level":"warn","msg":"slow","svc":"api","req":{"id":7,"n":2,"typed":true}}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+var benchErr = errors.New("boom")
+
+func BenchmarkInfoInterfaceFields(b *testing.B) {
+	l := discardLogger()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("request", field.String("method", "GET"), field.Int("status", 200), field.Int("bytes", 512), field.Err(benchErr))
+	}
+}
+
+func BenchmarkLogFields(b *testing.B) {
+	l := discardLogger()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.LogFields(zapcore.InfoLevel, "request", field.String("method", "GET"), field.Int("status", 200), field.Int("bytes", 512), field.Err(benchErr))
+	}
+}
@@ -1,8 +1,10 @@
 module github.com/comfforts/logger
 
-go 1.19
+go 1.22
 
 require (
+	github.com/klauspost/compress v1.18.0
+	go.uber.org/multierr v1.6.0
 	go.uber.org/zap v1.24.0
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 )
@@ -10,6 +12,5 @@ require (
 require (
 	github.com/BurntSushi/toml v1.2.1 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
-	go.uber.org/multierr v1.6.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
@@ -0,0 +1,236 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	internalQueueSize    = 256
+	internalFlushTimeout = time.Second
+)
+
+// internalLog is the shared diagnostics pipeline behind Internal.
+var internalLog = newInternalQueue()
+
+// Internal returns the logger for problems in the logging pipeline itself,
+// such as failing sinks, rotation or compression errors. Sinks and hooks
+// must report through it rather than through the logger they serve.
+//
+// Records are queued and written to stderr, or the writer set with
+// SetInternalOutput, by a single background goroutine, so reporting never
+// blocks and never re-enters the main pipeline. Records logged from within
+// the internal output itself are dropped and counted instead of recursing.
+func Internal() *zap.Logger {
+	return internalLog.logger
+}
+
+// SetInternalOutput redirects Internal diagnostics to w.
+func SetInternalOutput(w io.Writer) {
+	internalLog.setOutput(zapcore.AddSync(w))
+}
+
+// InternalDropped returns the number of Internal diagnostics dropped
+// because the queue was full or they were logged re-entrantly.
+func InternalDropped() uint64 {
+	return internalLog.dropped.Load()
+}
+
+type internalRecord struct {
+	ent    zapcore.Entry
+	fields []zapcore.Field
+	// flushed, if set, marks a Sync barrier rather than a record.
+	flushed chan struct{}
+}
+
+type internalQueue struct {
+	logger *zap.Logger
+	ch     chan internalRecord
+	start  sync.Once
+
+	mu  sync.Mutex
+	out zapcore.WriteSyncer
+	enc zapcore.Encoder
+
+	// writing is set while the drain goroutine writes a record, as a cheap
+	// pre-check before looking for re-entry on the caller's stack.
+	writing atomic.Bool
+	dropped atomic.Uint64
+	// reported is the dropped count last announced.
+	reported uint64
+}
+
+func newInternalQueue() *internalQueue {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	q := &internalQueue{
+		ch:  make(chan internalRecord, internalQueueSize),
+		out: zapcore.Lock(os.Stderr),
+		enc: zapcore.NewJSONEncoder(cfg),
+	}
+	q.logger = zap.New(&internalCore{LevelEnabler: zapcore.DebugLevel, q: q}).Named("logger")
+	return q
+}
+
+func (q *internalQueue) setOutput(ws zapcore.WriteSyncer) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.out = ws
+}
+
+func (q *internalQueue) enqueue(rec internalRecord) {
+	if q.reentered() {
+		q.dropped.Add(1)
+		return
+	}
+
+	q.start.Do(func() { go q.drain() })
+	select {
+	case q.ch <- rec:
+	default:
+		q.dropped.Add(1)
+	}
+}
+
+func (q *internalQueue) drain() {
+	for rec := range q.ch {
+		if rec.flushed != nil {
+			close(rec.flushed)
+			continue
+		}
+
+		q.writing.Store(true)
+		q.write(rec)
+		q.writing.Store(false)
+	}
+}
+
+func (q *internalQueue) write(rec internalRecord) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if dropped := q.dropped.Load(); dropped != q.reported {
+		q.encode(zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			Time:       time.Now(),
+			LoggerName: rec.ent.LoggerName,
+			Message:    "internal diagnostics dropped",
+		}, []zapcore.Field{zap.Uint64("dropped", dropped-q.reported)})
+		q.reported = dropped
+	}
+	q.encode(rec.ent, rec.fields)
+}
+
+func (q *internalQueue) encode(ent zapcore.Entry, fields []zapcore.Field) {
+	buf, err := q.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return
+	}
+	_, _ = q.out.Write(buf.Bytes())
+	buf.Free()
+}
+
+// reentered reports whether the caller is running inside the internal
+// output's Write, i.e. on the drain goroutine. Go has no goroutine-local
+// state, so this looks for the write frame on the caller's stack.
+func (q *internalQueue) reentered() bool {
+	if !q.writing.Load() {
+		return false
+	}
+
+	pcs := make([]uintptr, 128)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if frame.Function == internalWriteFunc {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// internalWriteFunc is the frame that marks re-entry from the output.
+const internalWriteFunc = "github.com/comfforts/logger.(*internalQueue).write"
+
+// flush waits until records queued so far are written. It returns at once
+// when called from within the internal output, which would deadlock.
+func (q *internalQueue) flush() error {
+	if q.reentered() {
+		return nil
+	}
+
+	done := make(chan struct{})
+	q.start.Do(func() { go q.drain() })
+	select {
+	case q.ch <- internalRecord{flushed: done}:
+	case <-time.After(internalFlushTimeout):
+		return nil
+	}
+
+	select {
+	case <-done:
+	case <-time.After(internalFlushTimeout):
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.out.Sync()
+}
+
+// internalCore hands records to the internal queue instead of encoding
+// them on the caller's goroutine.
+type internalCore struct {
+	zapcore.LevelEnabler
+	q       *internalQueue
+	context []zapcore.Field
+}
+
+func (c *internalCore) With(fields []zapcore.Field) zapcore.Core {
+	ctx := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	ctx = append(ctx, c.context...)
+	ctx = append(ctx, fields...)
+	return &internalCore{LevelEnabler: c.LevelEnabler, q: c.q, context: ctx}
+}
+
+func (c *internalCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *internalCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	all = append(all, c.context...)
+	all = append(all, fields...)
+	c.q.enqueue(internalRecord{ent: ent, fields: all})
+	return nil
+}
+
+func (c *internalCore) Sync() error {
+	return c.q.flush()
+}
+
+// internalErrorOutput receives zap's own error messages, such as failed
+// sink writes, and reports them through Internal.
+type internalErrorOutput struct{}
+
+func (internalErrorOutput) Write(p []byte) (int, error) {
+	Internal().Error("logger error", zap.String("error", strings.TrimSpace(string(p))))
+	return len(p), nil
+}
+
+func (internalErrorOutput) Sync() error {
+	return nil
+}
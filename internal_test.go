@@ -0,0 +1,101 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"go.uber.org/zap"
+)
+
+// slowWriter records writes, taking delay for each, and optionally logs
+// through Internal from within Write.
+type slowWriter struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	delay     time.Duration
+	reentrant bool
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	if w.reentrant {
+		logger.Internal().Warn("reported from the internal output")
+	}
+	time.Sleep(w.delay)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) count(msg string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.Count(w.buf.String(), msg)
+}
+
+func setInternalOutput(t *testing.T, w *slowWriter) {
+	t.Helper()
+	logger.SetInternalOutput(w)
+	t.Cleanup(func() {
+		_ = logger.Internal().Sync()
+		logger.SetInternalOutput(os.Stderr)
+	})
+}
+
+func TestInternalConcurrentReports(t *testing.T) {
+	w := &slowWriter{delay: 2 * time.Millisecond}
+	setInternalOutput(t, w)
+	before := logger.InternalDropped()
+
+	const goroutines, reports = 10, 10
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < reports; i++ {
+				logger.Internal().Error("concurrent report", zap.Int("i", i))
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := logger.Internal().Sync(); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	if got := w.count("concurrent report"); got != goroutines*reports {
+		t.Errorf("wrote %d reports, want %d", got, goroutines*reports)
+	}
+	if dropped := logger.InternalDropped() - before; dropped != 0 {
+		t.Errorf("dropped %d reports from ordinary goroutines", dropped)
+	}
+}
+
+func TestInternalRecursionIsDropped(t *testing.T) {
+	w := &slowWriter{reentrant: true}
+	setInternalOutput(t, w)
+	before := logger.InternalDropped()
+
+	for i := 0; i < 3; i++ {
+		logger.Internal().Error("original report")
+	}
+	if err := logger.Internal().Sync(); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+
+	if got := w.count("original report"); got != 3 {
+		t.Errorf("wrote %d original reports, want 3", got)
+	}
+	if got := w.count("reported from the internal output"); got != 0 {
+		t.Errorf("re-entrant reports were written %d times", got)
+	}
+	if dropped := logger.InternalDropped() - before; dropped < 3 {
+		t.Errorf("dropped %d re-entrant reports, want at least 3", dropped)
+	}
+}
@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Limits caps the size of individual records. Zero values disable the
+// corresponding limit.
+type Limits struct {
+	// MaxMessageBytes truncates the log message.
+	MaxMessageBytes int
+	// MaxFieldBytes truncates string, byte string, error and stringer values.
+	MaxFieldBytes int
+	// MaxFields caps the fields of a record, counting those added with
+	// With. Fields beyond it are dropped and counted in fields_truncated.
+	MaxFields int
+}
+
+func (lm Limits) enabled() bool {
+	return lm.MaxMessageBytes > 0 || lm.MaxFieldBytes > 0 || lm.MaxFields > 0
+}
+
+// limitCore enforces Limits before handing records to the wrapped core.
+type limitCore struct {
+	zapcore.Core
+	limits Limits
+	// context and contextDropped count the fields kept and dropped by With.
+	context        int
+	contextDropped int
+}
+
+func newLimitCore(core zapcore.Core, limits Limits) zapcore.Core {
+	if !limits.enabled() {
+		return core
+	}
+	return &limitCore{Core: core, limits: limits}
+}
+
+func (c *limitCore) With(fields []zapcore.Field) zapcore.Core {
+	fields, dropped := c.capFields(fields)
+	return &limitCore{
+		Core:           c.Core.With(c.truncateFields(fields)),
+		limits:         c.limits,
+		context:        c.context + countFields(fields),
+		contextDropped: c.contextDropped + dropped,
+	}
+}
+
+func (c *limitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *limitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = truncate(ent.Message, c.limits.MaxMessageBytes)
+
+	fields, dropped := c.capFields(fields)
+	if dropped += c.contextDropped; dropped > 0 {
+		fields = append(fields[:len(fields):len(fields)], zap.Int("fields_truncated", dropped))
+	}
+
+	return c.Core.Write(ent, c.truncateFields(fields))
+}
+
+// capFields keeps as many of fields as MaxFields leaves room for after the
+// context fields, returning them and the number dropped. The user input
+// escape flag is exempt and always kept.
+func (c *limitCore) capFields(fields []zapcore.Field) ([]zapcore.Field, int) {
+	max := c.limits.MaxFields
+	if max <= 0 {
+		return fields, 0
+	}
+
+	room := max - c.context
+	if room < 0 {
+		room = 0
+	}
+	if countFields(fields) <= room {
+		return fields, 0
+	}
+
+	kept := make([]zapcore.Field, 0, room+1)
+	dropped := 0
+	for _, f := range fields {
+		switch {
+		case isEscapeFlag(f):
+			kept = append(kept, f)
+		case room > 0:
+			kept = append(kept, f)
+			room--
+		default:
+			dropped++
+		}
+	}
+	return kept, dropped
+}
+
+// countFields returns the number of fields counted against MaxFields.
+func countFields(fields []zapcore.Field) int {
+	n := len(fields)
+	for _, f := range fields {
+		if isEscapeFlag(f) {
+			n--
+		}
+	}
+	return n
+}
+
+func isEscapeFlag(f zapcore.Field) bool {
+	return f.Key == USER_INPUT_ESCAPED_KEY && f.Type == zapcore.BoolType
+}
+
+// truncateFields returns fields with oversized values shortened. The input
+// slice is never modified since it may be shared with other cores.
+func (c *limitCore) truncateFields(fields []zapcore.Field) []zapcore.Field {
+	max := c.limits.MaxFieldBytes
+	if max <= 0 {
+		return fields
+	}
+
+	var out []zapcore.Field
+	for i, f := range fields {
+		tf, ok := truncateField(f, max)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i] = tf
+	}
+
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// truncateField reports whether f exceeded max and, if so, returns a string
+// field holding the truncated value.
+func truncateField(f zapcore.Field, max int) (zapcore.Field, bool) {
+	var s string
+	switch f.Type {
+	case zapcore.StringType:
+		s = f.String
+	case zapcore.ByteStringType:
+		s = string(f.Interface.([]byte))
+	case zapcore.ErrorType:
+		err, _ := f.Interface.(error)
+		if err == nil {
+			return f, false
+		}
+		s = err.Error()
+	case zapcore.StringerType:
+		sv, _ := f.Interface.(fmt.Stringer)
+		if sv == nil {
+			return f, false
+		}
+		s = sv.String()
+	default:
+		return f, false
+	}
+
+	if len(s) <= max {
+		return f, false
+	}
+	return zap.String(f.Key, truncate(s, max)), true
+}
+
+// truncate shortens s to at most max bytes, without splitting a UTF-8
+// sequence, and appends a marker noting how much was removed.
+func truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "...TRUNCATED " + formatBytes(len(s)-cut) + " bytes"
+}
+
+// formatBytes renders n with thousands separators, e.g. 9412 as "9,412".
+func formatBytes(n int) string {
+	s := strconv.Itoa(n)
+	if len(s) <= 3 {
+		return s
+	}
+
+	out := make([]byte, 0, len(s)+len(s)/3)
+	pre := len(s) % 3
+	if pre > 0 {
+		out = append(out, s[:pre]...)
+	}
+	for i := pre; i < len(s); i += 3 {
+		if len(out) > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, s[i:i+3]...)
+	}
+	return string(out)
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{"under limit", "hello", 10, "hello"},
+		{"disabled", "hello", 0, "hello"},
+		{"ascii", "hello world", 5, "hello...TRUNCATED 6 bytes"},
+		{"thousands", strings.Repeat("x", 9512), 100, strings.Repeat("x", 100) + "...TRUNCATED 9,412 bytes"},
+		// "é" is two bytes; cutting at 2 would split it.
+		{"rune boundary", "aéb", 2, "a...TRUNCATED 3 bytes"},
+		{"multibyte start", "日本語", 4, "日...TRUNCATED 6 bytes"},
+		{"first rune too long", "日本", 2, "...TRUNCATED 6 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.s, tt.max)
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) = %q is not valid UTF-8", tt.s, tt.max, got)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int]string{
+		0: "0", 999: "999", 1000: "1,000", 9412: "9,412", 123456: "123,456", 1234567: "1,234,567",
+	} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestMaxFieldsCountsContext(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	l := zap.New(newLimitCore(obs, Limits{MaxFields: 3, MaxFieldBytes: 4}))
+
+	l = l.With(zap.String("a", "1"), zap.String("b", "long value"))
+	l.Info("one", zap.Int("c", 3), zap.Int("d", 4), zap.Int("e", 5))
+	l.With(zap.Int("f", 6), zap.Int("g", 7)).Info("two", zap.Int("h", 8))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("got %d records, want 2", len(entries))
+	}
+
+	want := []map[string]interface{}{
+		{"a": "1", "b": "long...TRUNCATED 6 bytes", "c": int64(3), "fields_truncated": int64(2)},
+		{"a": "1", "b": "long...TRUNCATED 6 bytes", "f": int64(6), "fields_truncated": int64(2)},
+	}
+	for i, e := range entries {
+		got := e.ContextMap()
+		if len(got) != len(want[i]) {
+			t.Errorf("record %d fields = %v, want %v", i, got, want[i])
+			continue
+		}
+		for k, v := range want[i] {
+			if got[k] != v {
+				t.Errorf("record %d field %q = %#v, want %#v", i, k, got[k], v)
+			}
+		}
+	}
+}
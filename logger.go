@@ -4,9 +4,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/comfforts/logger/field"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 const DEFAULT_LOG_FILE_PATH = "logs/app.log"
@@ -24,57 +24,143 @@ type AppLogger interface {
 type appLogger struct {
 	*zap.Logger
 	config *AppLoggerConfig
+	sinks  []*sink
+	// fields logs on behalf of the AppLogger methods, skipping their frame.
+	fields *zap.Logger
+}
+
+// Logger names the concrete type returned by NewAppLogger and
+// NewTestAppLogger, for use in other packages' signatures.
+type Logger = appLogger
+
+var _ AppLogger = (*appLogger)(nil)
+
+func newAppLogger(logger *zap.Logger, config *AppLoggerConfig, sinks []*sink) *appLogger {
+	return &appLogger{
+		Logger: logger,
+		config: config,
+		sinks:  sinks,
+		fields: logger.WithOptions(zap.AddCallerSkip(1)),
+	}
+}
+
+// Info logs at InfoLevel. fields may mix zap.Field, slog.Attr and slog style
+// key/value pairs.
+func (l *appLogger) Info(msg string, fields ...interface{}) {
+	l.fields.Info(msg, toZapFields(fields)...)
+}
+
+// Warn logs at WarnLevel.
+func (l *appLogger) Warn(msg string, fields ...interface{}) {
+	l.fields.Warn(msg, toZapFields(fields)...)
+}
+
+// Debug logs at DebugLevel.
+func (l *appLogger) Debug(msg string, fields ...interface{}) {
+	l.fields.Debug(msg, toZapFields(fields)...)
+}
+
+// Error logs at ErrorLevel.
+func (l *appLogger) Error(msg string, fields ...interface{}) {
+	l.fields.Error(msg, toZapFields(fields)...)
+}
+
+// Panic logs at PanicLevel, then panics.
+func (l *appLogger) Panic(msg string, fields ...interface{}) {
+	l.fields.Panic(msg, toZapFields(fields)...)
+}
+
+// LogFields logs at lvl with typed fields. Unlike the level methods it
+// takes field.Field directly, so fields are not boxed into interfaces.
+func (l *appLogger) LogFields(lvl zapcore.Level, msg string, fs ...field.Field) {
+	if ce := l.fields.Check(lvl, msg); ce != nil {
+		ce.Write(field.ZapFields(fs)...)
+	}
+}
+
+// Fatal logs at FatalLevel, then exits.
+func (l *appLogger) Fatal(msg string, fields ...interface{}) {
+	l.fields.Fatal(msg, toZapFields(fields)...)
 }
 
 type AppLoggerConfig struct {
 	FilePath string
 	Name     string
 	Level    zapcore.Level
+	Limits   Limits
+	// Stacktrace overrides the default of full stack traces on Error and above.
+	Stacktrace *StacktraceConfig
+	// Sanitize cleans messages and string fields of unsafe characters.
+	Sanitize SanitizeMode
+	// StrictSanitization escapes values wrapped with UserInput.
+	StrictSanitization bool
+	// Compression compresses rotated log files with the chosen codec.
+	Compression Compression
+	// Rotation configures the rotating file output.
+	Rotation Rotation
+	// ContextAnnotations adds ctx deadline and cancellation state to records
+	// logged through the *Context methods.
+	ContextAnnotations bool
+	// SLO, if set, tracks rolling error rates of the records logged.
+	SLO *SLOTracker
+	// Routing, if set, replaces the default file and console outputs. Each
+	// sink receives records at or above its key level; Level is not applied.
+	Routing map[zapcore.Level]Sink
+	// Clock supplies record timestamps and drives native rotation. Defaults
+	// to the system clock.
+	Clock zapcore.Clock
 }
 
-func NewAppLogger(config *AppLoggerConfig) *appLogger {
-	logLevel := DEFAULT_LOG_LEVEL
-	filePath := DEFAULT_LOG_FILE_PATH
+func NewAppLogger(config *AppLoggerConfig, opts ...Option) *appLogger {
+	config = applyOptions(config, opts)
+	logger, sinks := buildLogger(config, zap.NewProductionEncoderConfig())
+	return newAppLogger(logger, config, sinks)
+}
 
-	if config != nil {
-		if config.FilePath != "" {
-			filePath = config.FilePath
-		}
+func NewAppZapLogger(config *AppLoggerConfig, opts ...Option) *zap.Logger {
+	logger, _ := buildLogger(applyOptions(config, opts), zap.NewProductionEncoderConfig())
+	return logger
+}
 
-		logLevel = config.Level
-	}
+func NewTestAppLogger(dir string, opts ...Option) *appLogger {
+	logCfg := applyOptions(testLoggerConfig(dir), opts)
+	logger, sinks := buildLogger(logCfg, zap.NewDevelopmentEncoderConfig())
+	return newAppLogger(logger, logCfg, sinks)
+}
 
-	cfg := zap.NewProductionEncoderConfig()
-	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+func NewTestAppZapLogger(dir string, opts ...Option) *zap.Logger {
+	logger, _ := buildLogger(applyOptions(testLoggerConfig(dir), opts), zap.NewDevelopmentEncoderConfig())
+	return logger
+}
 
-	fileEncoder := zapcore.NewJSONEncoder(cfg)
-	consoleEncoder := zapcore.NewConsoleEncoder(cfg)
-
-	writer := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   filePath,
-		MaxSize:    10, // megabytes
-		MaxBackups: 3,
-		MaxAge:     28, // days
-	})
-
-	core := zapcore.NewTee(
-		zapcore.NewCore(fileEncoder, writer, logLevel),
-		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), logLevel),
-	)
-	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
-	if config.Name != "" {
-		logger = logger.Named(config.Name)
+func testLoggerConfig(dir string) *AppLoggerConfig {
+	filePath := DEFAULT_LOG_FILE_PATH
+	if dir != "" {
+		filePath = filepath.Join(dir, DEFAULT_LOG_FILE_PATH)
 	}
 
-	return &appLogger{
-		Logger: logger,
-		config: config,
+	return &AppLoggerConfig{
+		Name:     "test",
+		Level:    DEFAULT_LOG_LEVEL,
+		FilePath: filePath,
 	}
 }
 
-func NewAppZapLogger(config *AppLoggerConfig) *zap.Logger {
+// buildLogger wires the file and console sinks for config into a zap logger
+// and returns the sinks so callers can report on them.
+func buildLogger(config *AppLoggerConfig, cfg zapcore.EncoderConfig) (*zap.Logger, []*sink) {
 	logLevel := DEFAULT_LOG_LEVEL
 	filePath := DEFAULT_LOG_FILE_PATH
+	name := ""
+	var limits Limits
+	var stacktrace *StacktraceConfig
+	sanitizeMode := SanitizeNone
+	strict := false
+	var compression Compression
+	var rotation Rotation
+	var slo *SLOTracker
+	var routing map[zapcore.Level]Sink
+	var clock zapcore.Clock = zapcore.DefaultClock
 
 	if config != nil {
 		if config.FilePath != "" {
@@ -82,100 +168,66 @@ func NewAppZapLogger(config *AppLoggerConfig) *zap.Logger {
 		}
 
 		logLevel = config.Level
+		name = config.Name
+		limits = config.Limits
+		stacktrace = config.Stacktrace
+		sanitizeMode = config.Sanitize
+		strict = config.StrictSanitization
+		compression = config.Compression
+		rotation = config.Rotation
+		slo = config.SLO
+		routing = config.Routing
+		if config.Clock != nil {
+			clock = config.Clock
+			if slo != nil {
+				slo.setClock(clock)
+			}
+		}
 	}
 
-	cfg := zap.NewProductionEncoderConfig()
 	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
 
-	fileEncoder := zapcore.NewJSONEncoder(cfg)
-	consoleEncoder := zapcore.NewConsoleEncoder(cfg)
-
-	writer := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   filePath,
-		MaxSize:    10, // megabytes
-		MaxBackups: 3,
-		MaxAge:     28, // days
-	})
-
-	core := zapcore.NewTee(
-		zapcore.NewCore(fileEncoder, writer, logLevel),
-		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), logLevel),
-	)
-	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
-	if config.Name != "" {
-		logger = logger.Named(config.Name)
-	}
-
-	return logger
-}
-
-func NewTestAppLogger(dir string) *appLogger {
-	logLevel := DEFAULT_LOG_LEVEL
-	filePath := DEFAULT_LOG_FILE_PATH
-
-	if dir != "" {
-		filePath = filepath.Join(dir, DEFAULT_LOG_FILE_PATH)
-	}
-
-	logCfg := AppLoggerConfig{
-		Level:    logLevel,
-		FilePath: filePath,
+	fileEncoder := newSanitizingEncoder(zapcore.NewJSONEncoder(cfg), sanitizeMode)
+	consoleEncoder := newSanitizingEncoder(zapcore.NewConsoleEncoder(cfg), sanitizeMode)
+
+	var cores []zapcore.Core
+	var sinks []*sink
+	if len(routing) > 0 {
+		cores, sinks = routedCores(routing, fileEncoder, consoleEncoder, rotation, compression, clock)
+	} else {
+		fileWriter := newFileWriter(filePath, rotation, compression, clock)
+		fileSink := newSink("file", fileWriter, fileWriter)
+		consoleSink := newSink("console", zapcore.AddSync(os.Stdout), nil)
+
+		cores = []zapcore.Core{
+			zapcore.NewCore(fileEncoder, fileSink, logLevel),
+			zapcore.NewCore(consoleEncoder, consoleSink, logLevel),
+		}
+		sinks = []*sink{fileSink, consoleSink}
 	}
 
-	cfg := zap.NewDevelopmentEncoderConfig()
-	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
-
-	fileEncoder := zapcore.NewJSONEncoder(cfg)
-	consoleEncoder := zapcore.NewConsoleEncoder(cfg)
-
-	writer := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   logCfg.FilePath,
-		MaxSize:    10, // megabytes
-		MaxBackups: 3,
-		MaxAge:     28, // days
-	})
-
-	core := zapcore.NewTee(
-		zapcore.NewCore(fileEncoder, writer, logCfg.Level),
-		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), logCfg.Level),
-	)
-	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)).Named("test")
-	return &appLogger{
-		Logger: logger,
-		config: &logCfg,
+	// Field rewriting wrappers go on each output so that every output keeps
+	// its own level check; a wrapper around the Tee would write to all of them.
+	for i, c := range cores {
+		c = newLimitCore(c, limits)
+		c = newUserInputCore(c, strict)
+		cores[i] = c
 	}
-}
 
-func NewTestAppZapLogger(dir string) *zap.Logger {
-	logLevel := DEFAULT_LOG_LEVEL
-	filePath := DEFAULT_LOG_FILE_PATH
+	core := zapcore.NewTee(cores...)
+	core = newSLOCore(core, slo)
 
-	if dir != "" {
-		filePath = filepath.Join(dir, DEFAULT_LOG_FILE_PATH)
+	opts := []zap.Option{zap.AddCaller(), zap.WithClock(clock), zap.ErrorOutput(internalErrorOutput{})}
+	if stacktrace != nil {
+		core = newStackCore(core, stacktrace)
+	} else {
+		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
 	}
 
-	logCfg := AppLoggerConfig{
-		Level:    logLevel,
-		FilePath: filePath,
+	logger := zap.New(core, opts...)
+	if name != "" {
+		logger = logger.Named(name)
 	}
 
-	cfg := zap.NewDevelopmentEncoderConfig()
-	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
-
-	fileEncoder := zapcore.NewJSONEncoder(cfg)
-	consoleEncoder := zapcore.NewConsoleEncoder(cfg)
-
-	writer := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   logCfg.FilePath,
-		MaxSize:    10, // megabytes
-		MaxBackups: 3,
-		MaxAge:     28, // days
-	})
-
-	core := zapcore.NewTee(
-		zapcore.NewCore(fileEncoder, writer, logCfg.Level),
-		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), logCfg.Level),
-	)
-	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)).Named("test")
-	return logger
+	return logger, sinks
 }
@@ -0,0 +1,63 @@
+package logtest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+var update = flag.Bool("logtest.update", false, "rewrite logtest golden files")
+
+// DefaultStart is the time NewLogger's clock starts at.
+var DefaultStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewLogger returns a logger writing JSON records at all levels to a
+// MemorySink, timestamped by a fake Clock starting at DefaultStart. opts
+// are applied after the test defaults.
+func NewLogger(t testing.TB, opts ...logger.Option) (*logger.Logger, *MemorySink, *Clock) {
+	t.Helper()
+
+	sink := NewMemorySink()
+	clock := NewClock(DefaultStart)
+	opts = append([]logger.Option{
+		logger.WithClock(clock),
+		logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+			zapcore.DebugLevel: logger.WriterSink("memory", sink),
+		}),
+	}, opts...)
+
+	l := logger.NewAppLogger(&logger.AppLoggerConfig{Name: "test"}, opts...)
+	t.Cleanup(func() { _ = l.Sync() })
+	return l, sink, clock
+}
+
+// AssertGolden compares got with the contents of the golden file at path,
+// relative to the test's working directory. Run tests with
+// -logtest.update to write got to the file instead.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -logtest.update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output does not match %s\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
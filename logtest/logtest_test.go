@@ -0,0 +1,87 @@
+package logtest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger/logtest"
+)
+
+func TestGolden(t *testing.T) {
+	l, sink, clock := logtest.NewLogger(t)
+
+	l.Info("started", "port", 8080, "tls", false)
+	clock.Add(1500 * time.Millisecond)
+	l.Warn("slow request", "route", "/api", "took", 1200*time.Millisecond)
+
+	logtest.AssertGolden(t, "testdata/basic.golden", sink.Bytes())
+}
+
+func TestClockTimestamps(t *testing.T) {
+	l, sink, clock := logtest.NewLogger(t)
+
+	l.Info("one")
+	clock.Add(90 * time.Second)
+	l.Info("two")
+	clock.Set(logtest.DefaultStart.Add(24 * time.Hour))
+	l.Info("three")
+
+	records, err := sink.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		logtest.DefaultStart,
+		logtest.DefaultStart.Add(90 * time.Second),
+		logtest.DefaultStart.Add(24 * time.Hour),
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i, r := range records {
+		if !r.Time.Equal(want[i]) {
+			t.Errorf("record %d time = %v, want %v", i, r.Time, want[i])
+		}
+	}
+}
+
+func TestFailureInjection(t *testing.T) {
+	l, sink, _ := logtest.NewLogger(t)
+
+	sink.FailWrites(1, nil)
+	l.Info("lost")
+	if l.Healthy() {
+		t.Error("logger healthy after an injected write failure")
+	}
+	l.Info("kept")
+	if !l.Healthy() {
+		t.Error("logger unhealthy after the injected failures ran out")
+	}
+
+	sink.FailWrites(-1, nil)
+	l.Info("lost")
+	l.Info("lost")
+	sink.Heal()
+	l.Info("kept")
+
+	if got := sink.Writes(); got != 5 {
+		t.Errorf("Writes() = %d, want 5", got)
+	}
+	lines := sink.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("got %d records, want 2:\n%v", len(lines), lines)
+	}
+
+	errSync := errors.New("disk gone")
+	sink.FailSync(errSync)
+	if err := l.Sync(); !errors.Is(err, errSync) {
+		t.Errorf("Sync() = %v, want %v", err, errSync)
+	}
+	sink.FailSync(nil)
+
+	sink.Reset()
+	if sink.Writes() != 0 || len(sink.Lines()) != 0 {
+		t.Error("Reset left records behind")
+	}
+}
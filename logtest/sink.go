@@ -0,0 +1,134 @@
+package logtest
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/comfforts/logger"
+)
+
+// ErrInjected is returned by a MemorySink's failing writes unless another
+// error was injected.
+var ErrInjected = errors.New("logtest: injected write failure")
+
+// MemorySink collects written records in memory. It implements
+// zapcore.WriteSyncer and can be used with logger.WriterSink.
+type MemorySink struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	writes   int
+	failErr  error
+	failLeft int
+	syncErr  error
+	closed   bool
+}
+
+// NewMemorySink returns an empty sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.writes++
+	if s.failLeft != 0 {
+		if s.failLeft > 0 {
+			s.failLeft--
+		}
+		return 0, s.failErr
+	}
+	return s.buf.Write(p)
+}
+
+func (s *MemorySink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncErr
+}
+
+func (s *MemorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// FailWrites makes the next n writes fail with err, or every write until
+// Heal if n is negative. A nil err fails with ErrInjected.
+func (s *MemorySink) FailWrites(n int, err error) {
+	if err == nil {
+		err = ErrInjected
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failErr = err
+	s.failLeft = n
+}
+
+// FailSync makes Sync return err; nil restores successful syncs.
+func (s *MemorySink) FailSync(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncErr = err
+}
+
+// Heal cancels injected write failures.
+func (s *MemorySink) Heal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failLeft = 0
+}
+
+// Bytes returns everything written so far.
+func (s *MemorySink) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte(nil), s.buf.Bytes()...)
+}
+
+// Lines returns the written records, one per line.
+func (s *MemorySink) Lines() []string {
+	var lines []string
+	for _, l := range bytes.Split(s.Bytes(), []byte("\n")) {
+		if len(l) > 0 {
+			lines = append(lines, string(l))
+		}
+	}
+	return lines
+}
+
+// Records decodes the written JSON records.
+func (s *MemorySink) Records() ([]*logger.Record, error) {
+	var records []*logger.Record
+	err := logger.ReadRecords(bytes.NewReader(s.Bytes()), func(r *logger.Record) error {
+		records = append(records, r)
+		return nil
+	})
+	return records, err
+}
+
+// Writes returns the number of write attempts, including failed ones.
+func (s *MemorySink) Writes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writes
+}
+
+// Closed reports whether Close was called.
+func (s *MemorySink) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Reset discards written records.
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf.Reset()
+	s.writes = 0
+}
@@ -0,0 +1,83 @@
+package logger
+
+import "go.uber.org/zap/zapcore"
+
+// Option adjusts an AppLoggerConfig at construction time.
+type Option func(*AppLoggerConfig)
+
+// applyOptions returns a copy of config with opts applied, leaving the
+// caller's config untouched.
+func applyOptions(config *AppLoggerConfig, opts []Option) *AppLoggerConfig {
+	if len(opts) == 0 {
+		return config
+	}
+
+	cfg := AppLoggerConfig{Level: DEFAULT_LOG_LEVEL}
+	if config != nil {
+		cfg = *config
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &cfg
+}
+
+// WithStrictSanitization escapes line breaks, ANSI escape sequences and
+// other control characters in values wrapped with UserInput, and flags
+// records where that happened.
+func WithStrictSanitization() Option {
+	return func(c *AppLoggerConfig) {
+		c.StrictSanitization = true
+	}
+}
+
+// WithCompression compresses rotated log files using codec at level.
+func WithCompression(codec Codec, level int) Option {
+	return func(c *AppLoggerConfig) {
+		c.Compression = Compression{Codec: codec, Level: level}
+	}
+}
+
+// WithRotationEngine selects the implementation behind the rotating file.
+func WithRotationEngine(engine RotationEngine) Option {
+	return func(c *AppLoggerConfig) {
+		c.Rotation.Engine = engine
+	}
+}
+
+// WithContextAnnotations makes the *Context log methods record the time
+// left until the context's deadline and whether it was already canceled.
+func WithContextAnnotations() Option {
+	return func(c *AppLoggerConfig) {
+		c.ContextAnnotations = true
+	}
+}
+
+// WithSLOTracker feeds every record logged to tracker.
+func WithSLOTracker(tracker *SLOTracker) Option {
+	return func(c *AppLoggerConfig) {
+		c.SLO = tracker
+	}
+}
+
+// WithLevelRouting sends records to sinks by level, replacing the default
+// file and console outputs. Each sink receives records at or above its key:
+//
+//	logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+//		zapcore.DebugLevel: logger.ConsoleSink(),
+//		zapcore.InfoLevel:  logger.FileSink("logs/app.log"),
+//		zapcore.ErrorLevel: logger.WriterSink("alerts", conn),
+//	})
+func WithLevelRouting(routes map[zapcore.Level]Sink) Option {
+	return func(c *AppLoggerConfig) {
+		c.Routing = routes
+	}
+}
+
+// WithClock sets the clock used for record timestamps and native rotation,
+// e.g. a fake clock in tests.
+func WithClock(clock zapcore.Clock) Option {
+	return func(c *AppLoggerConfig) {
+		c.Clock = clock
+	}
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"go.uber.org/zap"
+)
+
+// DefaultLogPath returns the conventional log file location for appName on
+// the current platform:
+//
+//	Linux and other Unix: $XDG_STATE_HOME/<app>/<app>.log, ~/.local/state if unset
+//	macOS:                ~/Library/Logs/<app>/<app>.log
+//	Windows:              %LOCALAPPDATA%\<app>\Logs\<app>.log
+func DefaultLogPath(appName string) (string, error) {
+	if appName == "" {
+		return "", errors.New("app name is required")
+	}
+	file := appName + ".log"
+
+	switch runtime.GOOS {
+	case "windows":
+		dir := os.Getenv("LOCALAPPDATA")
+		if dir == "" {
+			return "", errors.New("%LOCALAPPDATA% is not set")
+		}
+		return filepath.Join(dir, appName, "Logs", file), nil
+	case "darwin", "ios":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Logs", appName, file), nil
+	}
+
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName, file), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", appName, file), nil
+}
+
+// WithPlatformDefaults writes the log file to DefaultLogPath(appName)
+// instead of DEFAULT_LOG_FILE_PATH, and names the logger appName if no name
+// is set. If the platform location can't be determined the file path is
+// left unchanged.
+func WithPlatformDefaults(appName string) Option {
+	return func(c *AppLoggerConfig) {
+		path, err := DefaultLogPath(appName)
+		if err != nil {
+			Internal().Warn("no platform log location, using configured path",
+				zap.String("app", appName), zap.Error(err))
+		} else {
+			c.FilePath = path
+		}
+
+		if c.Name == "" {
+			c.Name = appName
+		}
+	}
+}
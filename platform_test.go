@@ -0,0 +1,112 @@
+package logger_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/comfforts/logger"
+)
+
+func skipUnlessXDG(t *testing.T) {
+	t.Helper()
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios":
+		t.Skipf("XDG paths are not used on %s", runtime.GOOS)
+	}
+}
+
+func TestDefaultLogPathXDG(t *testing.T) {
+	skipUnlessXDG(t)
+
+	state := t.TempDir()
+	tests := []struct {
+		name string
+		xdg  string
+		want string
+	}{
+		{"xdg set", state, filepath.Join(state, "svc", "svc.log")},
+		{"xdg relative", "relative/state", "/home/test/.local/state/svc/svc.log"},
+		{"xdg unset", "", "/home/test/.local/state/svc/svc.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", "/home/test")
+			t.Setenv("XDG_STATE_HOME", tt.xdg)
+
+			got, err := logger.DefaultLogPath("svc")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DefaultLogPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultLogPathDarwin(t *testing.T) {
+	if runtime.GOOS != "darwin" {
+		t.Skip("macOS only")
+	}
+	t.Setenv("HOME", "/Users/test")
+
+	got, err := logger.DefaultLogPath("svc")
+	if want := "/Users/test/Library/Logs/svc/svc.log"; err != nil || got != want {
+		t.Errorf("DefaultLogPath() = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestDefaultLogPathWindows(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("Windows only")
+	}
+	t.Setenv("LOCALAPPDATA", `C:\Users\test\AppData\Local`)
+
+	got, err := logger.DefaultLogPath("svc")
+	if want := `C:\Users\test\AppData\Local\svc\Logs\svc.log`; err != nil || got != want {
+		t.Errorf("DefaultLogPath() = %q, %v, want %q", got, err, want)
+	}
+
+	t.Setenv("LOCALAPPDATA", "")
+	if _, err := logger.DefaultLogPath("svc"); err == nil {
+		t.Error("DefaultLogPath() succeeded without LOCALAPPDATA")
+	}
+}
+
+func TestDefaultLogPathEmptyName(t *testing.T) {
+	if _, err := logger.DefaultLogPath(""); err == nil {
+		t.Error("DefaultLogPath(\"\") succeeded")
+	}
+}
+
+func TestWithPlatformDefaults(t *testing.T) {
+	skipUnlessXDG(t)
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	want := filepath.Join(state, "svc", "svc.log")
+
+	tests := []struct {
+		name     string
+		app      string
+		config   logger.AppLoggerConfig
+		wantName string
+		wantPath string
+	}{
+		{"explicit name kept", "svc", logger.AppLoggerConfig{Name: "api", FilePath: "logs/app.log"}, "api", want},
+		{"name defaulted", "svc", logger.AppLoggerConfig{}, "svc", want},
+		{"no app name", "", logger.AppLoggerConfig{Name: "api", FilePath: "logs/app.log"}, "api", "logs/app.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+			logger.WithPlatformDefaults(tt.app)(&c)
+			if c.Name != tt.wantName || c.FilePath != tt.wantPath {
+				t.Errorf("config = {Name: %q, FilePath: %q}, want {Name: %q, FilePath: %q}",
+					c.Name, c.FilePath, tt.wantName, tt.wantPath)
+			}
+		})
+	}
+}
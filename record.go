@@ -0,0 +1,269 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Record is a single log record: an entry plus its fields. Records built
+// from zap entries or decoded from JSON log files can be cloned, modified
+// and re-emitted through another logger, e.g. in a log forwarder.
+type Record struct {
+	Time       time.Time
+	Level      zapcore.Level
+	LoggerName string
+	Message    string
+	Caller     string
+	Stack      string
+	Fields     []zapcore.Field
+}
+
+// NewRecord builds a Record from a zap entry and its fields.
+func NewRecord(ent zapcore.Entry, fields []zapcore.Field) *Record {
+	r := &Record{
+		Time:       ent.Time,
+		Level:      ent.Level,
+		LoggerName: ent.LoggerName,
+		Message:    ent.Message,
+		Stack:      ent.Stack,
+		Fields:     make([]zapcore.Field, len(fields)),
+	}
+	if ent.Caller.Defined {
+		r.Caller = ent.Caller.TrimmedPath()
+	}
+	copy(r.Fields, fields)
+	return r
+}
+
+// Clone returns a copy of r whose fields can be changed independently.
+// Nested JSON objects and arrays from DecodeRecord are copied too; other
+// values held by reference, such as errors and marshalers, are shared.
+func (r *Record) Clone() *Record {
+	c := *r
+	c.Fields = make([]zapcore.Field, len(r.Fields))
+	for i, f := range r.Fields {
+		if f.Type == zapcore.ReflectType {
+			f.Interface = cloneJSON(f.Interface)
+		}
+		c.Fields[i] = f
+	}
+	return &c
+}
+
+// cloneJSON deep-copies the maps and slices produced by decoding JSON.
+func cloneJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = cloneJSON(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = cloneJSON(e)
+		}
+		return s
+	}
+	return v
+}
+
+// With appends fields to r, accepting the same field forms as AppLogger.
+func (r *Record) With(fields ...interface{}) *Record {
+	r.Fields = append(r.Fields, toZapFields(fields)...)
+	return r
+}
+
+// Without removes fields with any of the given keys.
+func (r *Record) Without(keys ...string) *Record {
+	kept := r.Fields[:0]
+	for _, f := range r.Fields {
+		drop := false
+		for _, k := range keys {
+			if f.Key == k {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			kept = append(kept, f)
+		}
+	}
+	r.Fields = kept
+	return r
+}
+
+// Field returns the first field with key.
+func (r *Record) Field(key string) (zapcore.Field, bool) {
+	for _, f := range r.Fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return zapcore.Field{}, false
+}
+
+// Entry returns r as a zap entry.
+func (r *Record) Entry() zapcore.Entry {
+	ent := zapcore.Entry{
+		Time:       r.Time,
+		Level:      r.Level,
+		LoggerName: r.LoggerName,
+		Message:    r.Message,
+		Stack:      r.Stack,
+	}
+
+	if i := strings.LastIndexByte(r.Caller, ':'); i > 0 {
+		if line, err := strconv.Atoi(r.Caller[i+1:]); err == nil {
+			ent.Caller = zapcore.EntryCaller{Defined: true, File: r.Caller[:i], Line: line}
+		}
+	}
+	return ent
+}
+
+// Emit writes r through l's outputs, keeping its original time, level,
+// name and caller. Records below l's level are skipped. Panic and Fatal
+// records are written without panicking or exiting.
+func (r *Record) Emit(l *zap.Logger) {
+	if ce := l.Core().Check(r.Entry(), nil); ce != nil {
+		ce.Write(r.Fields...)
+	}
+}
+
+// recordKeys maps the JSON keys written by this package's encoders onto
+// Record's entry fields. NewAppLogger writes the production keys,
+// NewTestAppLogger the development ones.
+type recordKeys struct {
+	time, level, name, message, caller, stack string
+}
+
+var (
+	productionKeys  = recordKeys{"ts", "level", "logger", "msg", "caller", "stacktrace"}
+	developmentKeys = recordKeys{"T", "L", "N", "M", "C", "S"}
+)
+
+func (k recordKeys) has(key string) bool {
+	switch key {
+	case k.time, k.level, k.name, k.message, k.caller, k.stack:
+		return true
+	}
+	return false
+}
+
+// keysFor picks the key set a decoded line was written with.
+func keysFor(raw map[string]interface{}) recordKeys {
+	_, msg := raw[productionKeys.message]
+	_, lvl := raw[productionKeys.level]
+	if !msg && !lvl {
+		if _, ok := raw[developmentKeys.message]; ok {
+			return developmentKeys
+		}
+		if _, ok := raw[developmentKeys.level]; ok {
+			return developmentKeys
+		}
+	}
+	return productionKeys
+}
+
+// DecodeRecord parses one JSON line as written by this package's file
+// output, from either NewAppLogger or NewTestAppLogger. Integers decode to
+// Int64 or Uint64 fields and other numbers to Float64 fields, so large
+// IDs survive a round trip.
+func DecodeRecord(line []byte) (*Record, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	keys := keysFor(raw)
+
+	r := &Record{}
+	if s, ok := raw[keys.level].(string); ok {
+		if err := r.Level.UnmarshalText([]byte(s)); err != nil {
+			return nil, err
+		}
+	}
+	switch ts := raw[keys.time].(type) {
+	case string:
+		t, err := time.Parse("2006-01-02T15:04:05.000Z0700", ts)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ts: %w", err)
+		}
+		r.Time = t
+	case json.Number:
+		secs, err := ts.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("parsing ts: %w", err)
+		}
+		r.Time = time.Unix(0, int64(secs*float64(time.Second)))
+	}
+	r.LoggerName, _ = raw[keys.name].(string)
+	r.Message, _ = raw[keys.message].(string)
+	r.Caller, _ = raw[keys.caller].(string)
+	r.Stack, _ = raw[keys.stack].(string)
+
+	names := make([]string, 0, len(raw))
+	for k := range raw {
+		if !keys.has(k) {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		r.Fields = append(r.Fields, decodedField(k, raw[k]))
+	}
+	return r, nil
+}
+
+// decodedField converts a decoded JSON value to a field. Top-level numbers
+// become typed numeric fields; nested numbers stay json.Number, which
+// re-encodes verbatim.
+func decodedField(key string, v interface{}) zapcore.Field {
+	n, ok := v.(json.Number)
+	if !ok {
+		return zap.Any(key, v)
+	}
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return zap.Int64(key, i)
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return zap.Uint64(key, u)
+	}
+	f, _ := n.Float64()
+	return zap.Float64(key, f)
+}
+
+// ReadRecords decodes JSON log lines from rd, calling fn for each record
+// until rd is exhausted or fn returns an error.
+func ReadRecords(rd io.Reader, fn func(*Record) error) error {
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		r, err := DecodeRecord(line)
+		if err != nil {
+			return err
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDecodeRecordNumbers(t *testing.T) {
+	line := []byte(`{"level":"info","ts":"2024-01-02T03:04:05.000Z","msg":"m",` +
+		`"id":9007199254740993,"big":18446744073709551615,"neg":-3,"ratio":0.25,"nested":{"n":9007199254740993}}`)
+
+	r, err := DecodeRecord(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]zapcore.Field{
+		"id":    zap.Int64("id", 9007199254740993),
+		"big":   zap.Uint64("big", math.MaxUint64),
+		"neg":   zap.Int64("neg", -3),
+		"ratio": zap.Float64("ratio", 0.25),
+	}
+	for k, w := range want {
+		f, ok := r.Field(k)
+		if !ok {
+			t.Fatalf("field %q missing", k)
+		}
+		if !f.Equals(w) {
+			t.Errorf("field %q = %#v, want %#v", k, f, w)
+		}
+	}
+
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, r.Fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const nested = `"nested":{"n":9007199254740993}`
+	if got := buf.String(); !strings.Contains(got, nested) {
+		t.Errorf("re-encoded %s, want it to contain %s", got, nested)
+	}
+}
+
+func TestDecodeRecordDevelopmentKeys(t *testing.T) {
+	dir := t.TempDir()
+	l := NewTestAppLogger(dir)
+	l.Warn("hello", zap.Uint64("id", math.MaxUint64), zap.String("k", "v"))
+	l.Sync()
+
+	f, err := os.Open(filepath.Join(dir, DEFAULT_LOG_FILE_PATH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []*Record
+	if err := ReadRecords(f, func(r *Record) error {
+		records = append(records, r)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+
+	r := records[0]
+	if r.Level != zapcore.WarnLevel || r.Message != "hello" || r.LoggerName != "test" {
+		t.Errorf("entry = %v %q %q, want warn \"hello\" \"test\"", r.Level, r.Message, r.LoggerName)
+	}
+	if r.Time.IsZero() || r.Caller == "" {
+		t.Errorf("time %v and caller %q should be set", r.Time, r.Caller)
+	}
+	if len(r.Fields) != 2 {
+		t.Fatalf("fields = %v, want id and k only", r.Fields)
+	}
+	if id, _ := r.Field("id"); !id.Equals(zap.Uint64("id", math.MaxUint64)) {
+		t.Errorf("id = %#v", id)
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	line := []byte(`{"level":"warn","ts":"2024-01-02T03:04:05.000Z","logger":"svc","caller":"app/main.go:12",` +
+		`"msg":"m","user":"u1","secret":"s","meta":{"n":1,"tags":["a","b"]}}`)
+	orig, err := DecodeRecord(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := orig.Clone().Without("secret").With("forwarded", true)
+	c.Message = "m2"
+	meta, _ := c.Field("meta")
+	m := meta.Interface.(map[string]interface{})
+	m["n"] = "changed"
+	m["tags"].([]interface{})[0] = "z"
+
+	var buf strings.Builder
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	l := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(cfg), zapcore.AddSync(&buf), zapcore.InfoLevel))
+
+	c.Emit(l)
+	debug := c.Clone()
+	debug.Level = zapcore.DebugLevel
+	debug.Emit(l)
+
+	want := `{"level":"warn","ts":"2024-01-02T03:04:05.000Z","logger":"svc","caller":"app/main.go:12",` +
+		`"msg":"m2","meta":{"n":"changed","tags":["z","b"]},"user":"u1","forwarded":true}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("emitted\n%s\nwant\n%s", got, want)
+	}
+
+	// The original is untouched by changes to the clone.
+	if orig.Message != "m" || len(orig.Fields) != 3 {
+		t.Errorf("original = %q %v", orig.Message, orig.Fields)
+	}
+	if _, ok := orig.Field("secret"); !ok {
+		t.Error("original lost its secret field")
+	}
+	meta, _ = orig.Field("meta")
+	om := meta.Interface.(map[string]interface{})
+	if om["n"] != json.Number("1") || om["tags"].([]interface{})[0] != "a" {
+		t.Errorf("original meta changed: %v", om)
+	}
+}
@@ -0,0 +1,420 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// backupTimeFormat is the timestamp lumberjack puts in backup file names,
+// also the native engine's default.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+const (
+	DEFAULT_MAX_SIZE_MB  = 10
+	DEFAULT_MAX_BACKUPS  = 3
+	DEFAULT_MAX_AGE_DAYS = 28
+)
+
+// RotationEngine selects the implementation behind the file output.
+type RotationEngine string
+
+const (
+	// RotationLumberjack rotates with gopkg.in/natefinch/lumberjack.v2.
+	// Builds with the nolumberjack tag drop that dependency and use the
+	// native engine instead.
+	RotationLumberjack RotationEngine = "lumberjack"
+	// RotationNative rotates with this package's own writer, which adds
+	// time based rotation and configurable backup names.
+	RotationNative RotationEngine = "native"
+)
+
+// Rotation configures log file rotation. Zero values select defaults.
+type Rotation struct {
+	Engine     RotationEngine
+	MaxSize    int // megabytes
+	MaxBackups int
+	MaxAge     int // days
+	// Interval rotates the file on interval boundaries, e.g. 24h for daily
+	// files. Native engine only.
+	Interval time.Duration
+	// BackupTimeFormat is the time layout used in backup names, e.g.
+	// "2006-01-02" for date-stamped files. Native engine only.
+	BackupTimeFormat string
+}
+
+func (r Rotation) withDefaults() Rotation {
+	if r.Engine == "" {
+		r.Engine = DEFAULT_ROTATION_ENGINE
+	}
+	if r.MaxSize == 0 {
+		r.MaxSize = DEFAULT_MAX_SIZE_MB
+	}
+	if r.MaxBackups == 0 {
+		r.MaxBackups = DEFAULT_MAX_BACKUPS
+	}
+	if r.MaxAge == 0 {
+		r.MaxAge = DEFAULT_MAX_AGE_DAYS
+	}
+	if r.BackupTimeFormat == "" {
+		r.BackupTimeFormat = backupTimeFormat
+	}
+	return r
+}
+
+// newFileWriter returns the rotating writer for filePath, falling back to
+// stderr while the file can't be written.
+func newFileWriter(filePath string, rotation Rotation, compression Compression, clock zapcore.Clock) *fallbackWriter {
+	return newFallbackWriter(filePath, newRotator(filePath, rotation, compression, clock), clock)
+}
+
+// newRotator returns the configured rotation engine for filePath. clock
+// drives the native engine; lumberjack always uses the system clock.
+func newRotator(filePath string, rotation Rotation, compression Compression, clock zapcore.Clock) rotator {
+	rotation = rotation.withDefaults()
+
+	if rotation.Engine == RotationNative {
+		return newRotatingFile(filePath, rotation, compression, clock)
+	}
+	return newLumberjackRotator(filePath, rotation, compression, clock)
+}
+
+// rotatingFile is a native size and time based rotating file writer.
+type rotatingFile struct {
+	filename    string
+	rotation    Rotation
+	compression Compression
+	now         func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	nextTime time.Time
+
+	wg      sync.WaitGroup
+	running sync.Mutex
+}
+
+func newRotatingFile(filename string, rotation Rotation, compression Compression, clock zapcore.Clock) *rotatingFile {
+	return &rotatingFile{
+		filename:    filename,
+		rotation:    rotation,
+		compression: compression,
+		now:         clock.Now,
+	}
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.size+int64(len(p)) > r.maxBytes() || r.intervalElapsed() {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+// Rotate closes the current file, moves it to a backup and opens a new one.
+func (r *rotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rotate()
+}
+
+// Close closes the current file and waits for pending compression.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	err := r.closeFile()
+	r.mu.Unlock()
+
+	r.wg.Wait()
+	return err
+}
+
+func (r *rotatingFile) maxBytes() int64 {
+	return int64(r.rotation.MaxSize) * 1024 * 1024
+}
+
+func (r *rotatingFile) intervalElapsed() bool {
+	return r.rotation.Interval > 0 && !r.now().Before(r.nextTime)
+}
+
+func (r *rotatingFile) scheduleNext() {
+	if r.rotation.Interval > 0 {
+		r.nextTime = r.now().UTC().Truncate(r.rotation.Interval).Add(r.rotation.Interval)
+	}
+}
+
+// open appends to an existing log file or creates a new one.
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.filename), 0o755); err != nil {
+		return fmt.Errorf("can't make directories for new logfile: %w", err)
+	}
+
+	f, err := os.OpenFile(r.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = fi.Size()
+	r.scheduleNext()
+	return nil
+}
+
+func (r *rotatingFile) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(r.filename); err == nil {
+		if err := os.Rename(r.filename, r.backupName()); err != nil {
+			return err
+		}
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.compressAsync()
+	return nil
+}
+
+// backupName returns an unused backup path. Backups sharing a stamp get a
+// sequence number one past the highest already present, so the newest is
+// always ordered last even after older ones were pruned. With interval
+// rotation the stamp is the start of the period the file covers, otherwise
+// the rotation time.
+func (r *rotatingFile) backupName() string {
+	dir := filepath.Dir(r.filename)
+	base := filepath.Base(r.filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	ts := r.now().UTC()
+	if r.rotation.Interval > 0 && !r.nextTime.IsZero() {
+		ts = r.nextTime.Add(-r.rotation.Interval)
+	}
+	stamp := ts.Format(r.rotation.BackupTimeFormat)
+
+	seq, ok := lastBackupSeq(dir, prefix+stamp, ext, r.compression.Codec)
+	if !ok {
+		return filepath.Join(dir, prefix+stamp+ext)
+	}
+	return filepath.Join(dir, prefix+stamp+"."+strconv.Itoa(seq+1)+ext)
+}
+
+// lastBackupSeq returns the highest sequence number among the backups in
+// dir named name, name.N, optionally compressed, and whether there are any.
+func lastBackupSeq(dir, name, ext string, codec Codec) (int, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, false
+	}
+
+	last, found := 0, false
+	for _, e := range entries {
+		rest := strings.TrimSuffix(e.Name(), codec.Ext())
+		if !strings.HasSuffix(rest, ext) {
+			continue
+		}
+		rest = strings.TrimSuffix(rest, ext)
+		if !strings.HasPrefix(rest, name) {
+			continue
+		}
+		rest = strings.TrimPrefix(rest, name)
+
+		seq := 0
+		if rest != "" {
+			if rest[0] != '.' {
+				continue
+			}
+			n, err := strconv.Atoi(rest[1:])
+			if err != nil {
+				continue
+			}
+			seq = n
+		}
+		if !found || seq > last {
+			last, found = seq, true
+		}
+	}
+	return last, found
+}
+
+func (r *rotatingFile) compressAsync() {
+	policy := backupPolicy{
+		filename:    r.filename,
+		timeFormat:  r.rotation.BackupTimeFormat,
+		maxBackups:  r.rotation.MaxBackups,
+		maxAge:      r.rotation.MaxAge,
+		compression: r.compression,
+		now:         r.now(),
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		r.running.Lock()
+		defer r.running.Unlock()
+
+		if err := compressAndPrune(policy); err != nil {
+			Internal().Error("compressing rotated logs", zap.String("path", policy.filename), zap.Error(err))
+		}
+	}()
+}
+
+// backupPolicy describes how the backups of a log file are kept.
+type backupPolicy struct {
+	filename    string
+	timeFormat  string
+	maxBackups  int
+	maxAge      int // days
+	compression Compression
+	now         time.Time
+}
+
+type backupFile struct {
+	path string
+	ts   time.Time
+	seq  int
+}
+
+// compressAndPrune compresses every uncompressed backup of the log file,
+// then removes backups beyond maxBackups or older than maxAge.
+func compressAndPrune(p backupPolicy) error {
+	dir := filepath.Dir(p.filename)
+	base := filepath.Base(p.filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+	codecExt := p.compression.Codec.Ext()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var backups []backupFile
+	var errs []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		isCompressed := strings.HasSuffix(name, ext+codecExt)
+		stamp := strings.TrimPrefix(name, prefix)
+		if isCompressed {
+			stamp = strings.TrimSuffix(stamp, ext+codecExt)
+		} else if strings.HasSuffix(name, ext) {
+			stamp = strings.TrimSuffix(stamp, ext)
+		} else {
+			continue
+		}
+
+		ts, seq, ok := parseBackupStamp(p.timeFormat, stamp)
+		if !ok {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		if !isCompressed {
+			if err := p.compression.compressFile(path); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			path += codecExt
+		}
+		backups = append(backups, backupFile{path: path, ts: ts, seq: seq})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].ts.Equal(backups[j].ts) {
+			return backups[i].seq > backups[j].seq
+		}
+		return backups[i].ts.After(backups[j].ts)
+	})
+
+	cutoff := time.Time{}
+	if p.maxAge > 0 {
+		cutoff = p.now.Add(-time.Duration(p.maxAge) * 24 * time.Hour)
+	}
+	for i, b := range backups {
+		if (p.maxBackups > 0 && i >= p.maxBackups) || b.ts.Before(cutoff) {
+			if err := os.Remove(b.path); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// parseBackupStamp parses a backup's time stamp, optionally followed by a
+// ".N" sequence number.
+func parseBackupStamp(format, stamp string) (time.Time, int, bool) {
+	if ts, err := time.Parse(format, stamp); err == nil {
+		return ts, 0, true
+	}
+
+	i := strings.LastIndexByte(stamp, '.')
+	if i < 0 {
+		return time.Time{}, 0, false
+	}
+	seq, err := strconv.Atoi(stamp[i+1:])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	ts, err := time.Parse(format, stamp[:i])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return ts, seq, true
+}
@@ -0,0 +1,119 @@
+//go:build !nolumberjack
+
+package logger
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// DEFAULT_ROTATION_ENGINE is lumberjack unless built with the nolumberjack tag.
+const DEFAULT_ROTATION_ENGINE = RotationLumberjack
+
+func newLumberjackRotator(filePath string, rotation Rotation, compression Compression, _ zapcore.Clock) rotator {
+	lj := &lumberjack.Logger{
+		Filename:   filePath,
+		MaxSize:    rotation.MaxSize,
+		MaxBackups: rotation.MaxBackups,
+		MaxAge:     rotation.MaxAge,
+	}
+	if compression.Codec != CodecNone {
+		return newCompressingRotator(lj, compression)
+	}
+	return lj
+}
+
+// compressingRotator compresses lumberjack's backups after rotation and
+// prunes the compressed files, since lumberjack only manages gzip itself.
+// Rotations are detected by the log file changing identity across a write.
+type compressingRotator struct {
+	*lumberjack.Logger
+	compression Compression
+
+	mu      sync.Mutex
+	current os.FileInfo
+	init    bool
+
+	wg      sync.WaitGroup
+	running sync.Mutex
+}
+
+func newCompressingRotator(l *lumberjack.Logger, c Compression) *compressingRotator {
+	return &compressingRotator{Logger: l, compression: c}
+}
+
+func (r *compressingRotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	if !r.init {
+		r.current, _ = os.Stat(r.Filename)
+		r.init = true
+	}
+	n, err := r.Logger.Write(p)
+	rotated := r.fileChanged()
+	r.mu.Unlock()
+
+	if rotated {
+		r.compressAsync()
+	}
+	return n, err
+}
+
+// fileChanged reports whether the log file was replaced since it was last
+// seen, i.e. lumberjack moved it to a backup.
+func (r *compressingRotator) fileChanged() bool {
+	fi, err := os.Stat(r.Filename)
+	if err != nil {
+		return false
+	}
+	prev := r.current
+	r.current = fi
+	return prev != nil && !os.SameFile(prev, fi)
+}
+
+// Rotate forces a rotation and compresses the resulting backup.
+func (r *compressingRotator) Rotate() error {
+	r.mu.Lock()
+	err := r.Logger.Rotate()
+	r.current, _ = os.Stat(r.Filename)
+	r.init = true
+	r.mu.Unlock()
+
+	if err == nil {
+		r.compressAsync()
+	}
+	return err
+}
+
+// Close closes the current file and waits for pending compression.
+func (r *compressingRotator) Close() error {
+	err := r.Logger.Close()
+	r.wg.Wait()
+	return err
+}
+
+func (r *compressingRotator) compressAsync() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		r.running.Lock()
+		defer r.running.Unlock()
+
+		err := compressAndPrune(backupPolicy{
+			filename:    r.Filename,
+			timeFormat:  backupTimeFormat,
+			maxBackups:  r.MaxBackups,
+			maxAge:      r.MaxAge,
+			compression: r.compression,
+			now:         time.Now(),
+		})
+		if err != nil {
+			Internal().Error("compressing rotated logs", zap.String("path", r.Filename), zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,18 @@
+//go:build nolumberjack
+
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DEFAULT_ROTATION_ENGINE is lumberjack unless built with the nolumberjack tag.
+const DEFAULT_ROTATION_ENGINE = RotationNative
+
+// newLumberjackRotator stands in for the lumberjack engine in builds
+// without it, using the native engine instead.
+func newLumberjackRotator(filePath string, rotation Rotation, compression Compression, clock zapcore.Clock) rotator {
+	Internal().Warn("built without lumberjack, using native rotation", zap.String("path", filePath))
+	return newRotatingFile(filePath, rotation, compression, clock)
+}
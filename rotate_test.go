@@ -0,0 +1,169 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNativeIntervalRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := logtest.NewClock(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	l := logger.NewAppLogger(&logger.AppLoggerConfig{
+		FilePath: path,
+		Rotation: logger.Rotation{Interval: 24 * time.Hour, BackupTimeFormat: "2006-01-02"},
+	}, logger.WithClock(clock), logger.WithRotationEngine(logger.RotationNative))
+
+	l.Info("day one")
+	clock.Add(14 * time.Hour)
+	l.Info("day two")
+	clock.Add(24 * time.Hour)
+	l.Info("day three")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"app-2024-03-01.log": "day one",
+		"app-2024-03-02.log": "day two",
+		"app.log":            "day three",
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(want) {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Fatalf("log dir holds %v, want %d files", names, len(want))
+	}
+	for name, msg := range want {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(string(b), "\n"); got != 1 || !strings.Contains(string(b), msg) {
+			t.Errorf("%s = %q, want one %q record", name, b, msg)
+		}
+	}
+}
+
+// nativeFileLogger logs to a natively rotated file at path only.
+func nativeFileLogger(path string, clock *logtest.Clock, rotation logger.Rotation) *logger.Logger {
+	rotation.Engine = logger.RotationNative
+	return logger.NewAppLogger(&logger.AppLoggerConfig{Rotation: rotation},
+		logger.WithClock(clock),
+		logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+			zapcore.InfoLevel: logger.FileSink(path),
+		}),
+	)
+}
+
+func fileNames(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestNativeSizeRotationSequence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := logtest.NewClock(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	// Every 600 KB record after the first overflows the 1 MB file, and the
+	// frozen clock gives each backup the same stamp.
+	l := nativeFileLogger(path, clock, logger.Rotation{MaxSize: 1, MaxBackups: 10, BackupTimeFormat: "2006-01-02"})
+	big := strings.Repeat("x", 600*1024)
+	for i := 0; i < 4; i++ {
+		l.Info(big, "n", i)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"app-2024-03-01.1.log", "app-2024-03-01.2.log", "app-2024-03-01.log", "app.log"}
+	if got := fileNames(t, dir); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	for name, n := range map[string]string{
+		"app-2024-03-01.log":   `"n":0`,
+		"app-2024-03-01.1.log": `"n":1`,
+		"app-2024-03-01.2.log": `"n":2`,
+		"app.log":              `"n":3`,
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(string(b), "\n") != 1 || !strings.Contains(string(b), n) {
+			t.Errorf("%s does not hold exactly the %s record", name, n)
+		}
+	}
+}
+
+func TestNativeMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := logtest.NewClock(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	l := nativeFileLogger(path, clock, logger.Rotation{MaxSize: 1, MaxBackups: 2, BackupTimeFormat: "2006-01-02"})
+	big := strings.Repeat("x", 600*1024)
+	for i := 0; i < 5; i++ {
+		l.Info(big, "n", i)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The newest backups of a stamp are those with the highest sequence.
+	want := []string{"app-2024-03-01.2.log", "app-2024-03-01.3.log", "app.log"}
+	if got := fileNames(t, dir); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestNativeMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := logtest.NewClock(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	l := nativeFileLogger(path, clock, logger.Rotation{
+		MaxAge:           2,
+		MaxBackups:       10,
+		Interval:         24 * time.Hour,
+		BackupTimeFormat: "2006-01-02",
+	})
+	for day := 0; day < 5; day++ {
+		l.Info("record", "day", day)
+		clock.Add(24 * time.Hour)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last rotation happens on March 5th; backups stamped more than two
+	// days earlier are removed.
+	want := []string{"app-2024-03-03.log", "app-2024-03-04.log", "app.log"}
+	if got := fileNames(t, dir); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
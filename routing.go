@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sort"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Encoding selects how a Sink formats records.
+type Encoding string
+
+const (
+	EncodingJSON    Encoding = "json"
+	EncodingConsole Encoding = "console"
+)
+
+// Sink is an output destination used with level routing.
+type Sink struct {
+	// Name identifies the sink in Stats.
+	Name     string
+	Encoding Encoding
+	// Writer receives encoded records. If nil, File is opened as a rotating
+	// file using the logger's Rotation and Compression settings.
+	Writer zapcore.WriteSyncer
+	File   string
+
+	// closer is the writer WriterSink was given, kept because AddSync may
+	// wrap it in a type that hides its Close method.
+	closer io.Closer
+}
+
+// ConsoleSink writes human readable records to stdout.
+func ConsoleSink() Sink {
+	return Sink{Name: "console", Encoding: EncodingConsole, Writer: zapcore.AddSync(os.Stdout)}
+}
+
+// StderrSink writes human readable records to stderr.
+func StderrSink() Sink {
+	return Sink{Name: "stderr", Encoding: EncodingConsole, Writer: zapcore.AddSync(os.Stderr)}
+}
+
+// FileSink writes JSON records to a rotating file at path.
+func FileSink(path string) Sink {
+	return Sink{Name: "file", Encoding: EncodingJSON, File: path}
+}
+
+// WriterSink writes JSON records to w, e.g. a network connection. w is
+// closed with the logger if it implements io.Closer.
+func WriterSink(name string, w io.Writer) Sink {
+	return Sink{Name: name, Encoding: EncodingJSON, Writer: zapcore.AddSync(w), closer: sinkCloser(w)}
+}
+
+// routedCores builds one core per routed sink, each enabled from its map
+// key level upwards.
+func routedCores(
+	routes map[zapcore.Level]Sink,
+	jsonEncoder, consoleEncoder zapcore.Encoder,
+	rotation Rotation,
+	compression Compression,
+	clock zapcore.Clock,
+) ([]zapcore.Core, []*sink) {
+	levels := make([]zapcore.Level, 0, len(routes))
+	for lvl := range routes {
+		levels = append(levels, lvl)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	cores := make([]zapcore.Core, 0, len(levels))
+	sinks := make([]*sink, 0, len(levels))
+	for _, lvl := range levels {
+		route := routes[lvl]
+
+		var s *sink
+		if route.Writer != nil {
+			closer := route.closer
+			if closer == nil {
+				closer = sinkCloser(route.Writer)
+			}
+			s = newSink(route.Name, route.Writer, closer)
+		} else {
+			w := newFileWriter(route.File, rotation, compression, clock)
+			s = newSink(route.Name, w, w)
+		}
+
+		enc := jsonEncoder
+		if route.Encoding == EncodingConsole {
+			enc = consoleEncoder
+		}
+
+		cores = append(cores, zapcore.NewCore(enc.Clone(), s, lvl))
+		sinks = append(sinks, s)
+	}
+	return cores, sinks
+}
+
+// sinkCloser returns w as an io.Closer unless it is one of the standard
+// streams, which the logger must never close.
+func sinkCloser(w io.Writer) io.Closer {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	closer, _ := w.(io.Closer)
+	return closer
+}
@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevelRoutingWithWrappers(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  logger.Option
+	}{
+		{"none", func(*logger.AppLoggerConfig) {}},
+		{"limits", func(c *logger.AppLoggerConfig) { c.Limits = logger.Limits{MaxFields: 5, MaxFieldBytes: 64} }},
+		{"strict", logger.WithStrictSanitization()},
+		{"slo", logger.WithSLOTracker(logger.NewSLOTracker(time.Minute, logger.ClassifyByField("route", zapcore.ErrorLevel)))},
+		{"stacktrace", func(c *logger.AppLoggerConfig) {
+			c.Stacktrace = &logger.StacktraceConfig{Level: zapcore.DebugLevel, Depth: 4}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var debug, errs bytes.Buffer
+			l := logger.NewAppLogger(nil, tt.opt, logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+				zapcore.DebugLevel: logger.WriterSink("debug", &debug),
+				zapcore.ErrorLevel: logger.WriterSink("errors", &errs),
+			}))
+
+			l.Debug("debug record", "route", "/a")
+			l.Error("error record", "route", "/a")
+
+			if !strings.Contains(debug.String(), "debug record") || !strings.Contains(debug.String(), "error record") {
+				t.Errorf("debug sink missing records:\n%s", debug.String())
+			}
+			if strings.Contains(errs.String(), "debug record") {
+				t.Errorf("error sink received a debug record:\n%s", errs.String())
+			}
+			if !strings.Contains(errs.String(), "error record") {
+				t.Errorf("error sink missing error record:\n%s", errs.String())
+			}
+		})
+	}
+}
+
+func TestLevelRoutingStacktraceAndSLO(t *testing.T) {
+	tracker := logger.NewSLOTracker(time.Minute, logger.ClassifyByField("route", zapcore.ErrorLevel))
+
+	var debug, errs bytes.Buffer
+	l := logger.NewAppLogger(nil,
+		logger.WithSLOTracker(tracker),
+		func(c *logger.AppLoggerConfig) {
+			c.Stacktrace = &logger.StacktraceConfig{Level: zapcore.ErrorLevel, Depth: 2}
+		},
+		logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+			zapcore.DebugLevel: logger.WriterSink("debug", &debug),
+			zapcore.ErrorLevel: logger.WriterSink("errors", &errs),
+		}),
+	)
+
+	l.Info("ok", "route", "/a")
+	l.Error("failed", "route", "/a")
+
+	if !strings.Contains(errs.String(), `"stacktrace"`) {
+		t.Errorf("error record has no stack trace:\n%s", errs.String())
+	}
+
+	// Each record is counted once, not once per output.
+	st := tracker.Stats("/a")
+	if st.Total != 2 || st.Errors != 1 {
+		t.Errorf("Stats(/a) = %+v, want 2 records with 1 error", st)
+	}
+}
+
+type closingWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *closingWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestWriterSinkClosedWithLogger(t *testing.T) {
+	w := &closingWriter{}
+	l := logger.NewAppLogger(nil, logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+		zapcore.InfoLevel: logger.WriterSink("conn", w),
+	}))
+
+	l.Info("record")
+	l.Close()
+
+	if !w.closed {
+		t.Error("WriterSink writer was not closed with the logger")
+	}
+	if !strings.Contains(w.String(), "record") {
+		t.Errorf("writer missing record:\n%s", w.String())
+	}
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const DEFAULT_RUNTIME_STATS_INTERVAL = time.Minute
+
+// StartRuntimeStats logs Go runtime statistics every interval until ctx is
+// done. It returns immediately; reporting happens in a background goroutine.
+// A non-positive interval selects DEFAULT_RUNTIME_STATS_INTERVAL.
+func StartRuntimeStats(ctx context.Context, l *zap.Logger, interval time.Duration) {
+	l = l.WithOptions(zap.WithCaller(false))
+	if interval <= 0 {
+		interval = DEFAULT_RUNTIME_STATS_INTERVAL
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var lastNumGC uint32
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				lastNumGC = logRuntimeStats(l, lastNumGC)
+			}
+		}
+	}()
+}
+
+// logRuntimeStats writes one runtime stats record and returns the GC count
+// it observed, used to report pauses since the previous record.
+func logRuntimeStats(l *zap.Logger, lastNumGC uint32) uint32 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	fields := []zap.Field{
+		zap.Int("goroutines", runtime.NumGoroutine()),
+		zap.Uint64("heap_inuse_bytes", ms.HeapInuse),
+		zap.Uint64("heap_alloc_bytes", ms.HeapAlloc),
+		zap.Uint64("heap_objects", ms.HeapObjects),
+		zap.Uint32("gc_count", ms.NumGC),
+		zap.Uint32("gc_since_last", ms.NumGC-lastNumGC),
+		zap.Duration("gc_pause_total", time.Duration(ms.PauseTotalNs)),
+		zap.Duration("gc_pause_max_recent", maxRecentPause(&ms, lastNumGC)),
+	}
+	if ms.NumGC > 0 {
+		fields = append(fields, zap.Duration("gc_pause_last", time.Duration(ms.PauseNs[(ms.NumGC+255)%256])))
+	}
+	if fds, ok := openFDCount(); ok {
+		fields = append(fields, zap.Int("open_fds", fds))
+	}
+
+	l.Info("runtime stats", fields...)
+	return ms.NumGC
+}
+
+// maxRecentPause returns the longest GC pause since lastNumGC, limited to
+// the 256 pauses the runtime keeps.
+func maxRecentPause(ms *runtime.MemStats, lastNumGC uint32) time.Duration {
+	n := ms.NumGC - lastNumGC
+	if n > 256 {
+		n = 256
+	}
+
+	var max uint64
+	for i := uint32(0); i < n; i++ {
+		if p := ms.PauseNs[(ms.NumGC-i+255)%256]; p > max {
+			max = p
+		}
+	}
+	return time.Duration(max)
+}
+
+// openFDCount reports the number of open file descriptors where the
+// platform exposes them through /proc or /dev/fd.
+func openFDCount() (int, bool) {
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		entries, err := os.ReadDir(dir)
+		if err == nil {
+			return len(entries), true
+		}
+	}
+	return 0, false
+}
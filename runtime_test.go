@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogRuntimeStats(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := zap.New(core)
+
+	runtime.GC()
+	numGC := logRuntimeStats(l, 0)
+	runtime.GC()
+	logRuntimeStats(l, numGC)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("got %d records, want 2", len(entries))
+	}
+
+	for _, key := range []string{
+		"goroutines", "heap_inuse_bytes", "heap_alloc_bytes", "heap_objects",
+		"gc_count", "gc_since_last", "gc_pause_total", "gc_pause_max_recent", "gc_pause_last",
+	} {
+		if _, ok := entries[1].ContextMap()[key]; !ok {
+			t.Errorf("runtime stats record missing %q", key)
+		}
+	}
+	if entries[1].Message != "runtime stats" {
+		t.Errorf("message = %q", entries[1].Message)
+	}
+
+	second := entries[1].ContextMap()
+	if got := second["gc_count"].(uint32); got <= numGC {
+		t.Errorf("gc_count = %d, want more than %d", got, numGC)
+	}
+	if got := second["gc_since_last"].(uint32); got < 1 {
+		t.Errorf("gc_since_last = %d, want at least 1", got)
+	}
+}
+
+func TestStartWithNonPositiveInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Neither goroutine may panic creating its ticker; give them time to
+	// start before the test returns.
+	StartRuntimeStats(ctx, zap.NewNop(), 0)
+	NewSLOTracker(time.Minute, nil).StartSummary(ctx, zap.NewNop(), -1)
+	time.Sleep(20 * time.Millisecond)
+}
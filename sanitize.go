@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// SanitizeMode selects how unsafe characters in messages and string fields
+// are handled before encoding.
+type SanitizeMode int
+
+const (
+	// SanitizeNone writes messages and values verbatim.
+	SanitizeNone SanitizeMode = iota
+	// SanitizeEscape replaces invalid UTF-8, control characters and line
+	// breaks with visible escape sequences such as \n and \x00.
+	SanitizeEscape
+	// SanitizeStrip removes invalid UTF-8, control characters and line breaks.
+	SanitizeStrip
+)
+
+// sanitizingEncoder cleans the message and top-level string values before
+// delegating to the wrapped encoder, so a single record always encodes to a
+// single well-formed line. Fields added with With reach the encoder through
+// AddString and AddByteString; other keys and nested values are escaped by
+// the JSON encoding both encoders use for fields.
+type sanitizingEncoder struct {
+	zapcore.Encoder
+	mode SanitizeMode
+}
+
+func newSanitizingEncoder(enc zapcore.Encoder, mode SanitizeMode) zapcore.Encoder {
+	if mode == SanitizeNone {
+		return enc
+	}
+	return &sanitizingEncoder{Encoder: enc, mode: mode}
+}
+
+func (e *sanitizingEncoder) Clone() zapcore.Encoder {
+	return &sanitizingEncoder{Encoder: e.Encoder.Clone(), mode: e.mode}
+}
+
+func (e *sanitizingEncoder) AddString(key, value string) {
+	e.Encoder.AddString(sanitize(key, e.mode), sanitize(value, e.mode))
+}
+
+func (e *sanitizingEncoder) AddByteString(key string, value []byte) {
+	e.Encoder.AddString(sanitize(key, e.mode), sanitize(string(value), e.mode))
+}
+
+func (e *sanitizingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = sanitize(ent.Message, e.mode)
+	ent.LoggerName = sanitize(ent.LoggerName, e.mode)
+
+	var out []zapcore.Field
+	for i, f := range fields {
+		sf, ok := sanitizeField(f, e.mode)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i] = sf
+	}
+	if out != nil {
+		fields = out
+	}
+
+	return e.Encoder.EncodeEntry(ent, fields)
+}
+
+// sanitizeField reports whether f carried unsafe text and, if so, returns a
+// string field holding the cleaned value.
+func sanitizeField(f zapcore.Field, mode SanitizeMode) (zapcore.Field, bool) {
+	var s string
+	switch f.Type {
+	case zapcore.StringType:
+		s = f.String
+	case zapcore.ByteStringType:
+		s = string(f.Interface.([]byte))
+	case zapcore.ErrorType:
+		err, _ := f.Interface.(error)
+		if err == nil {
+			return f, false
+		}
+		s = err.Error()
+	case zapcore.StringerType:
+		sv, _ := f.Interface.(fmt.Stringer)
+		if sv == nil {
+			return f, false
+		}
+		s = sv.String()
+	default:
+		if !needsSanitizing(f.Key) {
+			return f, false
+		}
+		f.Key = sanitize(f.Key, mode)
+		return f, true
+	}
+
+	if !needsSanitizing(s) && !needsSanitizing(f.Key) {
+		return f, false
+	}
+	return zap.String(sanitize(f.Key, mode), sanitize(s, mode)), true
+}
+
+func needsSanitizing(s string) bool {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || unicode.IsControl(r) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// sanitize escapes or strips invalid UTF-8 and control characters in s.
+func sanitize(s string, mode SanitizeMode) string {
+	if mode == SanitizeNone || !needsSanitizing(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			if mode == SanitizeEscape {
+				b.WriteString(`\x`)
+				b.WriteString(hex2(s[i]))
+			}
+		case unicode.IsControl(r):
+			if mode == SanitizeEscape {
+				b.WriteString(escapeControl(r))
+			}
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+func escapeControl(r rune) string {
+	switch r {
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	}
+	if r < 0x100 {
+		return `\x` + hex2(byte(r))
+	}
+	return `\u` + strconv.FormatInt(int64(r), 16)
+}
+
+func hex2(c byte) string {
+	const digits = "0123456789abcdef"
+	return string([]byte{digits[c>>4], digits[c&0xF]})
+}
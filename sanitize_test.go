@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func sanitizingLogger(mode SanitizeMode, console bool) (*zap.Logger, *bytes.Buffer) {
+	cfg := zap.NewProductionEncoderConfig()
+	enc := zapcore.NewJSONEncoder(cfg)
+	if console {
+		enc = zapcore.NewConsoleEncoder(cfg)
+	}
+
+	var buf bytes.Buffer
+	core := zapcore.NewCore(newSanitizingEncoder(enc, mode), zapcore.AddSync(&buf), zapcore.DebugLevel)
+	return zap.New(core), &buf
+}
+
+func TestSanitizeContextFields(t *testing.T) {
+	tests := []struct {
+		mode SanitizeMode
+		want string
+	}{
+		{SanitizeEscape, `"bytes":"a\\nb\\x00c"`},
+		{SanitizeStrip, `"bytes":"abc"`},
+	}
+
+	for _, tt := range tests {
+		l, buf := sanitizingLogger(tt.mode, false)
+		l.With(zap.ByteString("bytes", []byte("a\nb\x00c"))).Info("m")
+
+		if got := buf.String(); !strings.Contains(got, tt.want) {
+			t.Errorf("mode %d: got %s, want it to contain %s", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func FuzzSanitize(f *testing.F) {
+	for _, seed := range []string{"", "plain", "line\nbreak", "\r\n\t", "\x00\x1b[31m", "bad\xffutf8", "日本 語", "\xe6\x97"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, mode := range []SanitizeMode{SanitizeEscape, SanitizeStrip} {
+			for _, console := range []bool{false, true} {
+				l, buf := sanitizingLogger(mode, console)
+				l = l.Named(s).With(zap.String(s, s), zap.ByteString("ctx_bytes", []byte(s)))
+				l.Info(s,
+					zap.String("str", s),
+					zap.ByteString("bytes", []byte(s)),
+					zap.Error(errors.New(s)),
+					zap.Int(s, 1),
+				)
+
+				out := buf.Bytes()
+				if i := bytes.IndexByte(out, '\n'); i != len(out)-1 {
+					t.Fatalf("mode %d console %v: record is not a single line: %q", mode, console, out)
+				}
+				if !utf8.Valid(out) {
+					t.Fatalf("mode %d console %v: record is not valid UTF-8: %q", mode, console, out)
+				}
+			}
+		}
+	})
+}
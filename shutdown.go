@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// Close flushes buffered records, including pending Internal diagnostics,
+// and releases the logger's outputs. Sync errors from stdout and stderr
+// attached to a pipe or terminal are not reported.
+// The logger must not be used afterwards.
+func (l *appLogger) Close() error {
+	err := l.Sync()
+	for _, s := range l.sinks {
+		if s.closer != nil {
+			err = multierr.Append(err, s.closer.Close())
+		}
+	}
+	_ = Internal().Sync()
+	return err
+}
+
+const DEFAULT_SHUTDOWN_GRACE = 5 * time.Second
+
+// SignalOption configures HandleSignals.
+type SignalOption func(*signalConfig)
+
+type signalConfig struct {
+	grace   time.Duration
+	noClose bool
+}
+
+// WithShutdownGrace sets how long HandleSignals waits after canceling the
+// context before closing the logger, DEFAULT_SHUTDOWN_GRACE by default.
+func WithShutdownGrace(grace time.Duration) SignalOption {
+	return func(c *signalConfig) {
+		c.grace = grace
+	}
+}
+
+// WithoutClose leaves closing the logger to the caller.
+func WithoutClose() SignalOption {
+	return func(c *signalConfig) {
+		c.noClose = true
+	}
+}
+
+// HandleSignals returns a context derived from ctx that is canceled on
+// SIGINT or SIGTERM. When a signal arrives it is logged, the logger is
+// synced and the context is canceled, so shutdown code can still log. The
+// logger is closed once the grace period has passed, unless WithoutClose
+// is given. Calling the returned cancel func stops signal handling without
+// closing the logger.
+func HandleSignals(ctx context.Context, l *appLogger, opts ...SignalOption) (context.Context, context.CancelFunc) {
+	cfg := signalConfig{grace: DEFAULT_SHUTDOWN_GRACE}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(sigCh)
+
+		select {
+		case <-ctx.Done():
+		case sig := <-sigCh:
+			// Restore default handling so a second signal terminates the process.
+			signal.Stop(sigCh)
+			l.WithOptions(zap.WithCaller(false)).Info("received shutdown signal", zap.String("signal", sig.String()))
+			_ = l.Sync()
+			cancel()
+
+			if !cfg.noClose {
+				time.Sleep(cfg.grace)
+				_ = l.Close()
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
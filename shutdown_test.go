@@ -0,0 +1,92 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCloseIgnoresStdStreamSyncErrors(t *testing.T) {
+	l := logger.NewTestAppLogger(t.TempDir())
+	l.Info("record")
+
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() = %v, want nil", err)
+	}
+}
+
+func memoryLogger(mem *logtest.MemorySink) *logger.Logger {
+	return logger.NewAppLogger(nil, logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+		zapcore.InfoLevel: logger.WriterSink("memory", mem),
+	}))
+}
+
+// signalSelf sends SIGTERM to the test process and waits for ctx to be
+// canceled by HandleSignals.
+func signalSelf(t *testing.T, ctx context.Context) {
+	t.Helper()
+
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Signal(syscall.SIGTERM); err != nil {
+		t.Skipf("can't signal self: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not canceled after SIGTERM")
+	}
+}
+
+func TestHandleSignalsCancelsBeforeClose(t *testing.T) {
+	mem := logtest.NewMemorySink()
+	l := memoryLogger(mem)
+
+	ctx, cancel := logger.HandleSignals(context.Background(), l, logger.WithShutdownGrace(200*time.Millisecond))
+	defer cancel()
+
+	signalSelf(t, ctx)
+	if mem.Closed() {
+		t.Fatal("logger closed before the grace period")
+	}
+	l.Info("shutting down")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !mem.Closed() {
+		if time.Now().After(deadline) {
+			t.Fatal("logger not closed after the grace period")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	lines := mem.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("got %d records, want the signal and shutdown records:\n%v", len(lines), lines)
+	}
+}
+
+func TestHandleSignalsWithoutClose(t *testing.T) {
+	mem := logtest.NewMemorySink()
+	l := memoryLogger(mem)
+
+	ctx, cancel := logger.HandleSignals(context.Background(), l, logger.WithShutdownGrace(time.Millisecond), logger.WithoutClose())
+	defer cancel()
+
+	signalSelf(t, ctx)
+	time.Sleep(100 * time.Millisecond)
+	if mem.Closed() {
+		t.Fatal("logger closed despite WithoutClose")
+	}
+	if err := l.Close(); err != nil || !mem.Closed() {
+		t.Errorf("Close() = %v, closed %v", err, mem.Closed())
+	}
+}
@@ -0,0 +1,276 @@
+package logger
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const DEFAULT_SLO_BUCKETS = 60
+const DEFAULT_SLO_SUMMARY_INTERVAL = time.Minute
+
+// Classifier assigns a record to an SLO series. It returns the series key,
+// whether the record counts as an error, and false to ignore the record.
+// fields include those added with With.
+type Classifier func(ent zapcore.Entry, fields []zapcore.Field) (key string, isError bool, ok bool)
+
+// ClassifyByField keys records by the string value of the named field and
+// counts records at or above errorLevel as errors. Records without the
+// field are ignored.
+func ClassifyByField(name string, errorLevel zapcore.Level) Classifier {
+	return func(ent zapcore.Entry, fields []zapcore.Field) (string, bool, bool) {
+		for i := len(fields) - 1; i >= 0; i-- {
+			f := fields[i]
+			if f.Key == name && f.Type == zapcore.StringType {
+				return f.String, ent.Level >= errorLevel, true
+			}
+		}
+		return "", false, false
+	}
+}
+
+// SLOStats summarizes one series over the tracker's rolling window.
+type SLOStats struct {
+	Key       string
+	Total     uint64
+	Errors    uint64
+	ErrorRate float64
+}
+
+// BudgetRemaining returns the fraction of the error budget left for an
+// objective such as 0.999, negative once the budget is exhausted.
+func (s SLOStats) BudgetRemaining(objective float64) float64 {
+	allowed := (1 - objective) * float64(s.Total)
+	if allowed <= 0 {
+		if s.Errors == 0 {
+			return 1
+		}
+		return 0
+	}
+	return 1 - float64(s.Errors)/allowed
+}
+
+// SLOTracker maintains rolling error rates for records classified by a
+// Classifier. Attach it to a logger with WithSLOTracker.
+type SLOTracker struct {
+	classify Classifier
+	bucket   time.Duration
+	buckets  int
+
+	mu     sync.Mutex
+	now    func() time.Time
+	series map[string]*sloSeries
+}
+
+type sloBucket struct {
+	index  int64
+	total  uint64
+	errors uint64
+}
+
+type sloSeries struct {
+	buckets []sloBucket
+}
+
+// NewSLOTracker returns a tracker reporting over the trailing window.
+// Records are placed in the window by their entry time; the window ends at
+// the system clock's current time, or that of the logger's Clock once the
+// tracker is attached to a logger built WithClock.
+func NewSLOTracker(window time.Duration, classify Classifier) *SLOTracker {
+	bucket := window / DEFAULT_SLO_BUCKETS
+	if bucket <= 0 {
+		bucket = time.Millisecond
+	}
+	return &SLOTracker{
+		classify: classify,
+		bucket:   bucket,
+		buckets:  DEFAULT_SLO_BUCKETS,
+		now:      time.Now,
+		series:   map[string]*sloSeries{},
+	}
+}
+
+// setClock makes the window end at clock's current time.
+func (t *SLOTracker) setClock(clock zapcore.Clock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.now = clock.Now
+}
+
+func (t *SLOTracker) index(ts time.Time) int64 {
+	return ts.UnixNano() / int64(t.bucket)
+}
+
+func (t *SLOTracker) observe(ent zapcore.Entry, fields []zapcore.Field) {
+	key, isError, ok := t.classify(ent, fields)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ts := ent.Time
+	if ts.IsZero() {
+		ts = t.now()
+	}
+	idx := t.index(ts)
+
+	s, ok := t.series[key]
+	if !ok {
+		s = &sloSeries{buckets: make([]sloBucket, t.buckets)}
+		t.series[key] = s
+	}
+
+	b := &s.buckets[idx%int64(t.buckets)]
+	if b.index != idx {
+		*b = sloBucket{index: idx}
+	}
+	b.total++
+	if isError {
+		b.errors++
+	}
+}
+
+// Stats returns the rolling window stats for key.
+func (t *SLOTracker) Stats(key string) SLOStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.stats(key, t.index(t.now()))
+}
+
+// Snapshot returns the rolling window stats of every series with records in
+// the window, sorted by key.
+func (t *SLOTracker) Snapshot() []SLOStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := t.index(t.now())
+	out := make([]SLOStats, 0, len(t.series))
+	for key := range t.series {
+		st := t.stats(key, idx)
+		if st.Total == 0 {
+			delete(t.series, key)
+			continue
+		}
+		out = append(out, st)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+func (t *SLOTracker) stats(key string, idx int64) SLOStats {
+	st := SLOStats{Key: key}
+
+	s, ok := t.series[key]
+	if !ok {
+		return st
+	}
+
+	oldest := idx - int64(t.buckets) + 1
+	for _, b := range s.buckets {
+		if b.index >= oldest && b.index <= idx {
+			st.Total += b.total
+			st.Errors += b.errors
+		}
+	}
+	if st.Total > 0 {
+		st.ErrorRate = float64(st.Errors) / float64(st.Total)
+	}
+	return st
+}
+
+// StartSummary logs one record per series every interval until ctx is done.
+// A non-positive interval selects DEFAULT_SLO_SUMMARY_INTERVAL.
+func (t *SLOTracker) StartSummary(ctx context.Context, l *zap.Logger, interval time.Duration) {
+	l = l.WithOptions(zap.WithCaller(false))
+	if interval <= 0 {
+		interval = DEFAULT_SLO_SUMMARY_INTERVAL
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, st := range t.Snapshot() {
+					l.Info("slo summary",
+						zap.String("slo_key", st.Key),
+						zap.Uint64("slo_total", st.Total),
+						zap.Uint64("slo_errors", st.Errors),
+						zap.Float64("slo_error_rate", st.ErrorRate),
+					)
+				}
+			}
+		}
+	}()
+}
+
+// sloCore feeds written records, with their context fields, to a tracker.
+type sloCore struct {
+	zapcore.Core
+	tracker *SLOTracker
+	context []zapcore.Field
+}
+
+func newSLOCore(core zapcore.Core, tracker *SLOTracker) zapcore.Core {
+	if tracker == nil {
+		return core
+	}
+	return &sloCore{Core: core, tracker: tracker}
+}
+
+func (c *sloCore) With(fields []zapcore.Field) zapcore.Core {
+	ctx := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	ctx = append(ctx, c.context...)
+	ctx = append(ctx, fields...)
+	return &sloCore{
+		Core:    c.Core.With(fields),
+		tracker: c.tracker,
+		context: ctx,
+	}
+}
+
+// Check adds an observer for the record and lets the wrapped core decide
+// which outputs the record goes to.
+func (c *sloCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		ce = ce.AddCore(ent, sloObserver{c})
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *sloCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.observe(ent, fields)
+	return c.Core.Write(ent, fields)
+}
+
+func (c *sloCore) observe(ent zapcore.Entry, fields []zapcore.Field) {
+	all := fields
+	if len(c.context) > 0 {
+		all = make([]zapcore.Field, 0, len(c.context)+len(fields))
+		all = append(all, c.context...)
+		all = append(all, fields...)
+	}
+	c.tracker.observe(ent, all)
+}
+
+// sloObserver records a checked entry with the tracker without writing it.
+type sloObserver struct {
+	*sloCore
+}
+
+func (o sloObserver) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	o.observe(ent, fields)
+	return nil
+}
@@ -0,0 +1,122 @@
+package logger_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSLOTrackerUsesLoggerClock(t *testing.T) {
+	tracker := logger.NewSLOTracker(time.Minute, logger.ClassifyByField("route", zapcore.ErrorLevel))
+	l, _, clock := logtest.NewLogger(t, logger.WithSLOTracker(tracker))
+
+	l.Info("ok", "route", "/a")
+	l.Error("failed", "route", "/a")
+	clock.Add(30 * time.Second)
+	l.Info("ok", "route", "/a")
+
+	st := tracker.Stats("/a")
+	if st.Total != 3 || st.Errors != 1 {
+		t.Fatalf("stats = %+v, want 3 records with 1 error", st)
+	}
+
+	// The first two records leave the window, the third is still in it.
+	clock.Add(45 * time.Second)
+	if st := tracker.Stats("/a"); st.Total != 1 || st.Errors != 0 {
+		t.Errorf("stats after 75s = %+v, want 1 record without errors", st)
+	}
+
+	clock.Add(time.Minute)
+	if snap := tracker.Snapshot(); len(snap) != 0 {
+		t.Errorf("snapshot after the window = %+v, want empty", snap)
+	}
+}
+
+func TestBudgetRemaining(t *testing.T) {
+	tests := []struct {
+		name      string
+		stats     logger.SLOStats
+		objective float64
+		want      float64
+	}{
+		{"no records", logger.SLOStats{}, 0.999, 1},
+		{"no errors", logger.SLOStats{Total: 1000}, 0.999, 1},
+		{"half spent", logger.SLOStats{Total: 2000, Errors: 1}, 0.999, 0.5},
+		{"exhausted", logger.SLOStats{Total: 1000, Errors: 1}, 0.999, 0},
+		{"overspent", logger.SLOStats{Total: 1000, Errors: 3}, 0.999, -2},
+		{"perfect objective met", logger.SLOStats{Total: 10}, 1, 1},
+		{"perfect objective missed", logger.SLOStats{Total: 10, Errors: 1}, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.stats.BudgetRemaining(tt.objective)
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("BudgetRemaining(%v) = %v, want %v", tt.objective, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartSummary(t *testing.T) {
+	tracker := logger.NewSLOTracker(time.Minute, logger.ClassifyByField("route", zapcore.ErrorLevel))
+	l, _, _ := logtest.NewLogger(t, logger.WithSLOTracker(tracker))
+
+	l.Info("ok", "route", "/b")
+	l.Error("failed", "route", "/b")
+	l.Info("ok", "route", "/a")
+	l.Info("unclassified")
+
+	summary, sink, _ := logtest.NewLogger(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	tracker.StartSummary(ctx, summary.Logger, 10*time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sink.Lines()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("no summary records written")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	records, err := sink.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		key           string
+		total, errors int64
+		rate          float64
+	}{
+		{"/a", 1, 0, 0},
+		{"/b", 2, 1, 0.5},
+	}
+	// Each tick writes one record per series, sorted by key.
+	for i, r := range records[:2] {
+		w := want[i]
+		key, _ := r.Field("slo_key")
+		total, _ := r.Field("slo_total")
+		errs, _ := r.Field("slo_errors")
+		rate, _ := r.Field("slo_error_rate")
+		if r.Message != "slo summary" || key.String != w.key || total.Integer != w.total || errs.Integer != w.errors {
+			t.Errorf("summary %d = %q %v %v %v, want %+v", i, r.Message, key.String, total.Integer, errs.Integer, w)
+		}
+		if got := rateValue(rate); got != w.rate {
+			t.Errorf("summary %d slo_error_rate = %v, want %v", i, got, w.rate)
+		}
+	}
+}
+
+// rateValue returns a decoded numeric field as a float64.
+func rateValue(f zapcore.Field) float64 {
+	if f.Type == zapcore.Float64Type {
+		return math.Float64frombits(uint64(f.Integer))
+	}
+	return float64(f.Integer)
+}
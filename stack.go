@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const DEFAULT_STACKTRACE_DEPTH = 32
+
+// StacktraceConfig controls which records carry a stack trace and how many
+// frames it holds.
+type StacktraceConfig struct {
+	// Level is the minimum level that gets a stack trace.
+	Level zapcore.Level
+	// Depth caps the number of frames captured, DEFAULT_STACKTRACE_DEPTH if zero.
+	Depth int
+}
+
+// stackCore attaches a depth-limited stack trace of the logging goroutine
+// to records at or above its level.
+type stackCore struct {
+	zapcore.Core
+	level zapcore.Level
+	depth int
+}
+
+func newStackCore(core zapcore.Core, cfg *StacktraceConfig) zapcore.Core {
+	depth := cfg.Depth
+	if depth <= 0 {
+		depth = DEFAULT_STACKTRACE_DEPTH
+	}
+	return &stackCore{Core: core, level: cfg.Level, depth: depth}
+}
+
+func (c *stackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stackCore{
+		Core:  c.Core.With(fields),
+		level: c.level,
+		depth: c.depth,
+	}
+}
+
+// Check captures the stack into the entry and lets the wrapped core decide
+// which outputs the record goes to.
+func (c *stackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Stack == "" && c.level.Enabled(ent.Level) && c.Enabled(ent.Level) {
+		ent.Stack = captureStack(c.depth)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *stackCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Stack == "" && c.level.Enabled(ent.Level) {
+		ent.Stack = captureStack(c.depth)
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// captureStack formats up to depth frames of the caller's stack, skipping
+// frames that belong to zap, log/slog or this package.
+func captureStack(depth int) string {
+	pcs := make([]uintptr, depth+32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	written := 0
+	for written < depth {
+		frame, more := frames.Next()
+		if !internalFrame(frame.Function) {
+			if written > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(frame.Function)
+			b.WriteString("\n\t")
+			b.WriteString(frame.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(frame.Line))
+			written++
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+func internalFrame(fn string) bool {
+	return strings.HasPrefix(fn, "go.uber.org/zap") ||
+		strings.HasPrefix(fn, "log/slog.") ||
+		strings.HasPrefix(fn, "github.com/comfforts/logger.")
+}
@@ -0,0 +1,63 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackFrames returns the function names in a stack captured by stackCore.
+func stackFrames(stack string) []string {
+	var fns []string
+	for _, line := range strings.Split(stack, "\n") {
+		if line != "" && !strings.HasPrefix(line, "\t") {
+			fns = append(fns, line)
+		}
+	}
+	return fns
+}
+
+func TestStacktraceConfig(t *testing.T) {
+	l, sink, _ := logtest.NewLogger(t, func(c *logger.AppLoggerConfig) {
+		c.Stacktrace = &logger.StacktraceConfig{Level: zapcore.WarnLevel, Depth: 3}
+	})
+
+	l.Info("zap below level")
+	l.Warn("zap at level")
+	l.Slog().Info("slog below level")
+	l.Slog().Warn("slog at level")
+
+	records, err := sink.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+
+	for _, r := range records {
+		frames := stackFrames(r.Stack)
+		if r.Level < zapcore.WarnLevel {
+			if r.Stack != "" {
+				t.Errorf("%q has a stack below the configured level:\n%s", r.Message, r.Stack)
+			}
+			continue
+		}
+
+		if len(frames) != 3 {
+			t.Errorf("%q has %d frames, want 3:\n%s", r.Message, len(frames), r.Stack)
+			continue
+		}
+		if !strings.HasSuffix(frames[0], "logger_test.TestStacktraceConfig") {
+			t.Errorf("%q starts at %s, want the test function", r.Message, frames[0])
+		}
+		for _, fn := range frames {
+			if strings.HasPrefix(fn, "log/slog.") || strings.HasPrefix(fn, "go.uber.org/zap") {
+				t.Errorf("%q includes logging frame %s", r.Message, fn)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkStats is a point-in-time snapshot of a single output's counters.
+type SinkStats struct {
+	Name    string
+	Records uint64
+	Bytes   uint64
+	Errors  uint64
+	// Drops counts records the output failed to write and that were lost.
+	// Records written to a fallback while the output is degraded count as
+	// errors but not drops.
+	Drops       uint64
+	LastError   string
+	LastErrorAt time.Time
+	// Failing reports whether the most recent write returned an error.
+	Failing bool
+}
+
+// sink wraps an output and counts what is written through it.
+type sink struct {
+	zapcore.WriteSyncer
+	name   string
+	closer io.Closer
+	std    bool
+
+	records atomic.Uint64
+	bytes   atomic.Uint64
+	errors  atomic.Uint64
+	drops   atomic.Uint64
+	failing atomic.Bool
+
+	mu          sync.Mutex
+	lastErr     error
+	lastErrorAt time.Time
+}
+
+// newSink wraps ws for counting. closer, if not nil, releases the
+// underlying output when the logger is closed.
+func newSink(name string, ws zapcore.WriteSyncer, closer io.Closer) *sink {
+	return &sink{
+		WriteSyncer: ws,
+		name:        name,
+		closer:      closer,
+		std:         ws == os.Stdout || ws == os.Stderr,
+	}
+}
+
+// degradable is implemented by outputs that can keep accepting writes
+// through a fallback while their destination is failing.
+type degradable interface {
+	degraded() error
+}
+
+// Write is called once per encoded record by zap's ioCore.
+func (s *sink) Write(p []byte) (int, error) {
+	n, err := s.WriteSyncer.Write(p)
+	s.records.Add(1)
+	s.bytes.Add(uint64(n))
+	if err != nil {
+		s.drops.Add(1)
+	}
+
+	observed := err
+	if d, ok := s.WriteSyncer.(degradable); ok && err == nil {
+		observed = d.degraded()
+	}
+	s.observe(observed)
+	return n, err
+}
+
+// Sync flushes the output. Standard streams attached to a pipe or terminal
+// can't be synced; those errors are ignored.
+func (s *sink) Sync() error {
+	err := s.WriteSyncer.Sync()
+	if s.std && (errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY)) {
+		return nil
+	}
+	return err
+}
+
+func (s *sink) observe(err error) {
+	if err == nil {
+		s.failing.Store(false)
+		return
+	}
+
+	s.errors.Add(1)
+	s.failing.Store(true)
+
+	s.mu.Lock()
+	s.lastErr = err
+	s.lastErrorAt = time.Now()
+	s.mu.Unlock()
+}
+
+func (s *sink) stats() SinkStats {
+	st := SinkStats{
+		Name:    s.name,
+		Records: s.records.Load(),
+		Bytes:   s.bytes.Load(),
+		Errors:  s.errors.Load(),
+		Drops:   s.drops.Load(),
+		Failing: s.failing.Load(),
+	}
+
+	s.mu.Lock()
+	if s.lastErr != nil {
+		st.LastError = s.lastErr.Error()
+		st.LastErrorAt = s.lastErrorAt
+	}
+	s.mu.Unlock()
+
+	return st
+}
+
+// Stats returns counters for each of the logger's outputs.
+func (l *appLogger) Stats() []SinkStats {
+	stats := make([]SinkStats, 0, len(l.sinks))
+	for _, s := range l.sinks {
+		stats = append(stats, s.stats())
+	}
+	return stats
+}
+
+// Healthy reports whether every output accepted its most recent write.
+// Suitable for inclusion in a service readiness check.
+func (l *appLogger) Healthy() bool {
+	for _, s := range l.sinks {
+		if s.failing.Load() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestStatsHealthyRecovers(t *testing.T) {
+	mem := logtest.NewMemorySink()
+	l := logger.NewAppLogger(nil, logger.WithLevelRouting(map[zapcore.Level]logger.Sink{
+		zapcore.InfoLevel: logger.WriterSink("memory", mem),
+	}))
+
+	l.Info("first")
+	if !l.Healthy() {
+		t.Fatal("logger unhealthy before any failure")
+	}
+
+	mem.FailWrites(2, nil)
+	l.Info("lost")
+	l.Info("lost")
+	if l.Healthy() {
+		t.Fatal("logger healthy while its sink is failing")
+	}
+
+	st := l.Stats()
+	if len(st) != 1 {
+		t.Fatalf("got %d sink stats, want 1", len(st))
+	}
+	if st[0].Name != "memory" || st[0].Records != 3 || st[0].Errors != 2 || st[0].Drops != 2 || !st[0].Failing {
+		t.Errorf("stats while failing = %+v", st[0])
+	}
+	if st[0].LastError != logtest.ErrInjected.Error() || st[0].LastErrorAt.IsZero() {
+		t.Errorf("last error = %q at %v", st[0].LastError, st[0].LastErrorAt)
+	}
+
+	l.Info("recovered")
+	if !l.Healthy() {
+		t.Fatal("logger still unhealthy after a successful write")
+	}
+	st = l.Stats()
+	if st[0].Records != 4 || st[0].Drops != 2 || st[0].Failing {
+		t.Errorf("stats after recovery = %+v", st[0])
+	}
+	if got := len(mem.Lines()); got != 2 {
+		t.Errorf("sink holds %d records, want 2", got)
+	}
+}
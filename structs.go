@@ -0,0 +1,363 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// REDACTED replaces the value of struct fields tagged `log:",redact"`.
+const REDACTED = "[REDACTED]"
+
+// maxStructDepth bounds recursion into nested and self-referencing values.
+const maxStructDepth = 8
+
+// Fields expands m into top-level fields of the record, in key order.
+// Struct values, including those inside slices and maps, follow the same
+// `log` tags as Struct.
+func Fields(m map[string]interface{}) zap.Field {
+	return zap.Inline(fieldMap(m))
+}
+
+// Struct logs the exported fields of v, a struct or pointer to struct, as
+// a nested object under key. Field names and handling follow `log` tags:
+//
+//	Name   string `log:"name"`          // logged as "name"
+//	Token  string `log:"-"`             // never logged
+//	Email  string `log:",redact"`       // logged as "[REDACTED]"
+//	Note   string `log:"note,omitempty"` // skipped when empty
+//
+// Nested structs, including those inside slices, arrays and maps, are
+// expanded with the same rules, as are the promoted fields of embedded
+// structs. Values that are not structs are logged with zap.Any.
+func Struct(key string, v interface{}) zap.Field {
+	rv, ok := structValue(reflect.ValueOf(v))
+	if !ok {
+		return zap.Any(key, v)
+	}
+	return zap.Object(key, structMarshaler{v: rv})
+}
+
+type fieldMap map[string]interface{}
+
+func (m fieldMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := addValue(enc, k, reflect.ValueOf(m[k]), 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type structMarshaler struct {
+	v     reflect.Value
+	depth int
+}
+
+func (s structMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return addStructFields(enc, s.v, s.depth)
+}
+
+// sliceMarshaler encodes a slice or array element by element.
+type sliceMarshaler struct {
+	v     reflect.Value
+	depth int
+}
+
+func (s sliceMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := 0; i < s.v.Len(); i++ {
+		if err := appendValue(enc, s.v.Index(i), s.depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapMarshaler encodes a map as an object, keys sorted by their string form.
+type mapMarshaler struct {
+	v     reflect.Value
+	depth int
+}
+
+func (m mapMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := m.v.MapKeys()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = mapKeyString(k)
+	}
+	idx := make([]int, len(keys))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool { return names[idx[a]] < names[idx[b]] })
+
+	for _, i := range idx {
+		if err := addValue(enc, names[i], m.v.MapIndex(keys[i]), m.depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addStructFields(enc zapcore.ObjectEncoder, v reflect.Value, depth int) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts := parseLogTag(sf.Tag.Get("log"))
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if sf.Anonymous && name == "" {
+			// Promote the fields of embedded structs, exported or not, as
+			// encoding/json does.
+			if ev, ok := structValue(fv); ok && !ownEncoding(fv) {
+				if depth < maxStructDepth {
+					if err := addStructFields(enc, ev, depth+1); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		if hasTagOption(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if hasTagOption(opts, "redact") {
+			enc.AddString(name, REDACTED)
+			continue
+		}
+
+		if err := addValue(enc, name, fv, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addValue adds v to enc under key, expanding structs, slices, arrays and
+// maps so that `log` tags apply at any depth.
+func addValue(enc zapcore.ObjectEncoder, key string, v reflect.Value, depth int) error {
+	if !v.IsValid() {
+		enc.AddReflected(key, nil)
+		return nil
+	}
+	if ownEncoding(v) {
+		zap.Any(key, v.Interface()).AddTo(enc)
+		return nil
+	}
+
+	ev := indirect(v)
+	if !ev.IsValid() {
+		enc.AddReflected(key, nil)
+		return nil
+	}
+	if ownEncoding(ev) {
+		zap.Any(key, ev.Interface()).AddTo(enc)
+		return nil
+	}
+
+	if isContainer(ev) && depth >= maxStructDepth {
+		enc.AddString(key, "...")
+		return nil
+	}
+
+	switch ev.Kind() {
+	case reflect.Struct:
+		return enc.AddObject(key, structMarshaler{v: ev, depth: depth + 1})
+	case reflect.Map:
+		return enc.AddObject(key, mapMarshaler{v: ev, depth: depth + 1})
+	case reflect.Slice, reflect.Array:
+		if ev.Type().Elem().Kind() == reflect.Uint8 && ev.CanInterface() {
+			zap.Any(key, ev.Interface()).AddTo(enc)
+			return nil
+		}
+		return enc.AddArray(key, sliceMarshaler{v: ev, depth: depth + 1})
+	case reflect.String:
+		enc.AddString(key, ev.String())
+	case reflect.Bool:
+		enc.AddBool(key, ev.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		enc.AddInt64(key, ev.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		enc.AddUint64(key, ev.Uint())
+	case reflect.Float32, reflect.Float64:
+		enc.AddFloat64(key, ev.Float())
+	case reflect.Complex64, reflect.Complex128:
+		enc.AddComplex128(key, ev.Complex())
+	default:
+		if ev.CanInterface() {
+			zap.Any(key, ev.Interface()).AddTo(enc)
+		}
+	}
+	return nil
+}
+
+// appendValue is addValue for array elements.
+func appendValue(enc zapcore.ArrayEncoder, v reflect.Value, depth int) error {
+	if !v.IsValid() {
+		return enc.AppendReflected(nil)
+	}
+	if ownEncoding(v) {
+		return appendAny(enc, v.Interface())
+	}
+
+	ev := indirect(v)
+	if !ev.IsValid() {
+		return enc.AppendReflected(nil)
+	}
+	if ownEncoding(ev) {
+		return appendAny(enc, ev.Interface())
+	}
+
+	if isContainer(ev) && depth >= maxStructDepth {
+		enc.AppendString("...")
+		return nil
+	}
+
+	switch ev.Kind() {
+	case reflect.Struct:
+		return enc.AppendObject(structMarshaler{v: ev, depth: depth + 1})
+	case reflect.Map:
+		return enc.AppendObject(mapMarshaler{v: ev, depth: depth + 1})
+	case reflect.Slice, reflect.Array:
+		if ev.Type().Elem().Kind() == reflect.Uint8 && ev.CanInterface() {
+			return enc.AppendReflected(ev.Interface())
+		}
+		return enc.AppendArray(sliceMarshaler{v: ev, depth: depth + 1})
+	case reflect.String:
+		enc.AppendString(ev.String())
+	case reflect.Bool:
+		enc.AppendBool(ev.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		enc.AppendInt64(ev.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		enc.AppendUint64(ev.Uint())
+	case reflect.Float32, reflect.Float64:
+		enc.AppendFloat64(ev.Float())
+	case reflect.Complex64, reflect.Complex128:
+		enc.AppendComplex128(ev.Complex())
+	default:
+		if ev.CanInterface() {
+			return enc.AppendReflected(ev.Interface())
+		}
+	}
+	return nil
+}
+
+// appendAny appends a value that encodes itself.
+func appendAny(enc zapcore.ArrayEncoder, v interface{}) error {
+	switch val := v.(type) {
+	case zapcore.ObjectMarshaler:
+		return enc.AppendObject(val)
+	case zapcore.ArrayMarshaler:
+		return enc.AppendArray(val)
+	case time.Time:
+		enc.AppendTime(val)
+	case error:
+		enc.AppendString(val.Error())
+	case fmt.Stringer:
+		enc.AppendString(val.String())
+	default:
+		return enc.AppendReflected(val)
+	}
+	return nil
+}
+
+// indirect follows pointers and interfaces, returning the zero Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// structValue returns the struct behind v, following pointers.
+func structValue(v reflect.Value) (reflect.Value, bool) {
+	v = indirect(v)
+	return v, v.IsValid() && v.Kind() == reflect.Struct
+}
+
+func isContainer(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// ownEncoding reports whether v should be left to its own encoding rather
+// than expanded: times, zap marshalers, stringers and errors.
+func ownEncoding(v reflect.Value) bool {
+	if !v.IsValid() || !v.CanInterface() {
+		return false
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return false
+	}
+
+	switch v.Interface().(type) {
+	case time.Time, zapcore.ObjectMarshaler, zapcore.ArrayMarshaler, fmt.Stringer, error:
+		return true
+	}
+	return false
+}
+
+// mapKeyString formats a map key, including keys reached through an
+// unexported field that can't be converted back to an interface.
+func mapKeyString(v reflect.Value) string {
+	if v.CanInterface() {
+		return fmt.Sprint(v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return v.Type().String()
+}
+
+func parseLogTag(tag string) (string, string) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, opts
+}
+
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comfforts/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type secret struct {
+	User     string `log:"user"`
+	Password string `log:"-"`
+	Email    string `log:"email,redact"`
+	Note     string `log:"note,omitempty"`
+}
+
+type audit struct {
+	At time.Time `log:"at"`
+}
+
+type request struct {
+	audit
+	ID     int               `log:"id"`
+	Owner  *secret           `log:"owner"`
+	Users  []secret          `log:"users"`
+	ByName map[string]secret `log:"by_name"`
+	Tags   []string          `log:"tags"`
+	Next   *request          `log:"next"`
+	hidden string
+}
+
+func encodeFields(t *testing.T, fields ...zap.Field) string {
+	t.Helper()
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg", EncodeTime: zapcore.RFC3339TimeEncoder})
+	buf, err := enc.EncodeEntry(zapcore.Entry{Message: "m"}, fields)
+	if err != nil {
+		t.Fatalf("EncodeEntry() = %v", err)
+	}
+	return buf.String()
+}
+
+func TestStructTags(t *testing.T) {
+	s := secret{User: "ann", Password: "hunter2", Email: "ann@example.com"}
+	req := &request{
+		audit:  audit{At: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		ID:     7,
+		Owner:  &s,
+		Users:  []secret{s, s},
+		ByName: map[string]secret{"ann": s},
+		Tags:   []string{"a", "b"},
+		hidden: "internal",
+	}
+	req.Next = req
+
+	tests := []struct {
+		name  string
+		field zap.Field
+	}{
+		{"Struct", logger.Struct("request", req)},
+		{"Fields", logger.Fields(map[string]interface{}{"request": req, "owner": s, "list": []secret{s}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := encodeFields(t, tt.field)
+
+			for _, leaked := range []string{"hunter2", "ann@example.com", "Password", "internal", "note"} {
+				if strings.Contains(out, leaked) {
+					t.Errorf("output contains %q:\n%s", leaked, out)
+				}
+			}
+			if !strings.Contains(out, `"email":"[REDACTED]"`) || !strings.Contains(out, `"user":"ann"`) {
+				t.Errorf("tagged fields missing:\n%s", out)
+			}
+		})
+	}
+
+	out := encodeFields(t, logger.Struct("request", req))
+	for _, want := range []string{
+		`"at":"2020-01-01T00:00:00Z"`, // promoted from the unexported embedded struct
+		`"id":7`,
+		`"tags":["a","b"]`,
+		`"by_name":{"ann":{"user":"ann","email":"[REDACTED]"}}`,
+		`"next":"..."`, // the self reference stops at the depth limit
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestStructNonStruct(t *testing.T) {
+	out := encodeFields(t, logger.Struct("n", 5), logger.Fields(map[string]interface{}{"b": 1, "a": "x"}))
+	if !strings.Contains(out, `"n":5,"a":"x","b":1`) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const USER_INPUT_ESCAPED_KEY = "user_input_escaped"
+
+// UserString marks a value as user controlled. It logs verbatim unless the
+// logger was built WithStrictSanitization.
+type UserString string
+
+func (s UserString) String() string {
+	return string(s)
+}
+
+// UserInput wraps a user-supplied value for logging, e.g.
+//
+//	l.Info("search", zap.Stringer("query", logger.UserInput(q)))
+func UserInput(s string) UserString {
+	return UserString(s)
+}
+
+// userInputCore escapes UserString values before they reach the encoder.
+type userInputCore struct {
+	zapcore.Core
+	// flagged is set once the escape flag is part of the context fields.
+	flagged bool
+}
+
+func newUserInputCore(core zapcore.Core, strict bool) zapcore.Core {
+	if !strict {
+		return core
+	}
+	return &userInputCore{Core: core}
+}
+
+func (c *userInputCore) With(fields []zapcore.Field) zapcore.Core {
+	fields, escaped := escapeUserInput(fields)
+	if escaped && !c.flagged {
+		fields = append(fields, zap.Bool(USER_INPUT_ESCAPED_KEY, true))
+	}
+	return &userInputCore{
+		Core:    c.Core.With(fields),
+		flagged: c.flagged || escaped,
+	}
+}
+
+func (c *userInputCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *userInputCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields, escaped := escapeUserInput(fields)
+	if escaped && !c.flagged {
+		fields = append(fields, zap.Bool(USER_INPUT_ESCAPED_KEY, true))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// escapeUserInput returns fields with unsafe UserString values escaped and
+// whether any were. The input slice is not modified.
+func escapeUserInput(fields []zapcore.Field) ([]zapcore.Field, bool) {
+	var out []zapcore.Field
+	for i, f := range fields {
+		if f.Type != zapcore.StringerType {
+			continue
+		}
+		us, ok := f.Interface.(UserString)
+		if !ok || !needsSanitizing(string(us)) {
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields), len(fields)+1)
+			copy(out, fields)
+		}
+		out[i] = zap.String(f.Key, sanitize(string(us), SanitizeEscape))
+	}
+
+	if out == nil {
+		return fields, false
+	}
+	return out, true
+}
@@ -0,0 +1,96 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/comfforts/logger"
+	"github.com/comfforts/logger/logtest"
+	"go.uber.org/zap"
+)
+
+const forged = "a\r\nlevel=info msg=forged\x1b[31m"
+
+// escapedForged is forged as escaped by WithStrictSanitization.
+const escapedForged = `a\r\nlevel=info msg=forged\x1b[31m`
+
+func TestUserInputStrict(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *logger.Logger)
+	}{
+		{"zap field", func(l *logger.Logger) { l.Info("m", zap.Stringer("q", logger.UserInput(forged))) }},
+		{"key value", func(l *logger.Logger) { l.Info("m", "q", logger.UserInput(forged)) }},
+		{"with", func(l *logger.Logger) { l.WithFields(zap.Stringer("q", logger.UserInput(forged))).Info("m") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, sink, _ := logtest.NewLogger(t, logger.WithStrictSanitization())
+			tt.log(l)
+
+			records, err := sink.Records()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1 {
+				t.Fatalf("got %d records, want 1", len(records))
+			}
+			if q, _ := records[0].Field("q"); q.String != escapedForged {
+				t.Errorf("q = %q, want %q", q.String, escapedForged)
+			}
+			if flag, ok := records[0].Field(logger.USER_INPUT_ESCAPED_KEY); !ok || flag.Integer != 1 {
+				t.Errorf("%s = %#v, want true", logger.USER_INPUT_ESCAPED_KEY, flag)
+			}
+		})
+	}
+}
+
+func TestUserInputFlaggedOnce(t *testing.T) {
+	l, sink, _ := logtest.NewLogger(t, logger.WithStrictSanitization())
+
+	child := l.WithFields(zap.Stringer("q", logger.UserInput(forged)))
+	child.Info("m", zap.Stringer("r", logger.UserInput(forged)))
+	child.WithFields(zap.Stringer("s", logger.UserInput(forged))).Info("m")
+
+	for _, line := range sink.Lines() {
+		if n := strings.Count(line, logger.USER_INPUT_ESCAPED_KEY); n != 1 {
+			t.Errorf("flag appears %d times in %s", n, line)
+		}
+	}
+}
+
+func TestUserInputVerbatimWithoutStrict(t *testing.T) {
+	l, sink, _ := logtest.NewLogger(t)
+	l.Info("m", "q", logger.UserInput(forged))
+
+	records, err := sink.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q, _ := records[0].Field("q"); q.String != forged {
+		t.Errorf("q = %q, want %q verbatim", q.String, forged)
+	}
+	if _, ok := records[0].Field(logger.USER_INPUT_ESCAPED_KEY); ok {
+		t.Errorf("%s set without strict sanitization", logger.USER_INPUT_ESCAPED_KEY)
+	}
+}
+
+func TestUserInputFlagSurvivesMaxFields(t *testing.T) {
+	l, sink, _ := logtest.NewLogger(t, logger.WithStrictSanitization(), func(c *logger.AppLoggerConfig) {
+		c.Limits = logger.Limits{MaxFields: 1}
+	})
+	l.Info("m", zap.Stringer("q", logger.UserInput(forged)), zap.Stringer("r", logger.UserInput(forged)))
+
+	records, err := sink.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := records[0]
+	if _, ok := r.Field(logger.USER_INPUT_ESCAPED_KEY); !ok {
+		t.Errorf("%s dropped by MaxFields: %v", logger.USER_INPUT_ESCAPED_KEY, sink.Lines())
+	}
+	if f, _ := r.Field("fields_truncated"); f.Integer != 1 {
+		t.Errorf("fields_truncated = %#v, want 1", f)
+	}
+}